@@ -16,6 +16,7 @@ const (
 	Anthropic  Provider = "anthropic"
 	Gemini     Provider = "gemini"
 	OpenRouter Provider = "openrouter"
+	TGI        Provider = "tgi"
 )
 
 // Role represents the role of a message sender
@@ -31,6 +32,9 @@ const (
 type Message struct {
 	Role    Role
 	Content string
+
+	// CacheControl marks a prompt cache breakpoint after this message (optional)
+	CacheControl *proto.CacheControl
 }
 
 // String returns the string representation of the provider
@@ -41,7 +45,7 @@ func (p Provider) String() string {
 // IsValid checks if the provider is valid
 func (p Provider) IsValid() bool {
 	switch p {
-	case OpenAI, Anthropic, Gemini, OpenRouter:
+	case OpenAI, Anthropic, Gemini, OpenRouter, TGI:
 		return true
 	default:
 		return false
@@ -90,8 +94,9 @@ func (c *Client) Invoke(ctx context.Context, provider Provider, messages []Messa
 	protoMessages := make([]*proto.ChatMessage, len(messages))
 	for i, msg := range messages {
 		protoMessages[i] = &proto.ChatMessage{
-			Role:    string(msg.Role),
-			Content: msg.Content,
+			Role:         string(msg.Role),
+			Content:      msg.Content,
+			CacheControl: msg.CacheControl,
 		}
 	}
 
@@ -140,8 +145,9 @@ func (c *Client) InvokeStream(ctx context.Context, provider Provider, messages [
 	protoMessages := make([]*proto.ChatMessage, len(messages))
 	for i, msg := range messages {
 		protoMessages[i] = &proto.ChatMessage{
-			Role:    string(msg.Role),
-			Content: msg.Content,
+			Role:         string(msg.Role),
+			Content:      msg.Content,
+			CacheControl: msg.CacheControl,
 		}
 	}
 
@@ -207,6 +213,53 @@ func WithTopK(topK int32) Option {
 	}
 }
 
+// WithRepetitionPenalty penalizes repeated tokens (1.0 means no penalty)
+func WithRepetitionPenalty(penalty float32) Option {
+	return func(req *proto.LLMRequest) {
+		req.RepetitionPenalty = penalty
+	}
+}
+
+// WithStop sets the sequences at which generation stops
+func WithStop(stop ...string) Option {
+	return func(req *proto.LLMRequest) {
+		req.Stop = stop
+	}
+}
+
+// WithPreviousResponseID continues a stored conversation (OpenAI Responses API)
+func WithPreviousResponseID(id string) Option {
+	return func(req *proto.LLMRequest) {
+		req.PreviousResponseId = id
+	}
+}
+
+// WithReasoningEffort sets how much reasoning o-series models do ("low", "medium", "high")
+func WithReasoningEffort(effort string) Option {
+	return func(req *proto.LLMRequest) {
+		req.ReasoningEffort = effort
+	}
+}
+
+// WithOpenRouterOptions sets OpenRouter routing preferences (provider order,
+// fallbacks, data collection policy, etc.)
+func WithOpenRouterOptions(opts *proto.OpenRouterOptions) Option {
+	return func(req *proto.LLMRequest) {
+		req.Openrouter = opts
+	}
+}
+
+// WithSafetySetting sets the block threshold for a harm category (Gemini),
+// e.g. WithSafetySetting("HARM_CATEGORY_HARASSMENT", "BLOCK_ONLY_HIGH")
+func WithSafetySetting(category, threshold string) Option {
+	return func(req *proto.LLMRequest) {
+		if req.SafetySettings == nil {
+			req.SafetySettings = make(map[string]string)
+		}
+		req.SafetySettings[category] = threshold
+	}
+}
+
 // WithCacheControl sets the caching behavior for the request
 func WithCacheControl(useCache bool, ttl int32) Option {
 	return func(req *proto.LLMRequest) {
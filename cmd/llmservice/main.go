@@ -4,9 +4,13 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"net/url"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -16,8 +20,11 @@ import (
 	"github.com/c0rtexR/llm_service/internal/provider"
 	"github.com/c0rtexR/llm_service/internal/provider/anthropic"
 	"github.com/c0rtexR/llm_service/internal/provider/gemini"
+	"github.com/c0rtexR/llm_service/internal/provider/keypool"
 	"github.com/c0rtexR/llm_service/internal/provider/openai"
 	"github.com/c0rtexR/llm_service/internal/provider/openrouter"
+	"github.com/c0rtexR/llm_service/internal/provider/retry"
+	"github.com/c0rtexR/llm_service/internal/provider/tgi"
 	"github.com/c0rtexR/llm_service/internal/server"
 	pb "github.com/c0rtexR/llm_service/proto"
 )
@@ -43,62 +50,217 @@ func main() {
 	// Initialize providers
 	providers := make(map[string]provider.LLMProvider)
 
+	// HTTP_* settings apply to every provider; <PROVIDER>_HTTP_* overrides them
+	transportFor := func(prefix string) provider.TransportConfig {
+		transport, err := transportFromEnv(prefix)
+		if err != nil {
+			logger.Fatal("invalid HTTP transport settings", zap.String("provider", prefix), zap.Error(err))
+		}
+		return transport
+	}
+
+	// <PROVIDER>_API_KEYS configures a pool of keys; requests are spread across them
+	keyPolicy, err := keyPoolPolicyFromEnv()
+	if err != nil {
+		logger.Fatal("invalid key pool settings", zap.Error(err))
+	}
+	pooled := func(name string, keys []string, build func(key string) provider.LLMProvider) provider.LLMProvider {
+		if len(keys) == 1 {
+			return build(keys[0])
+		}
+		poolKeys := make([]keypool.Key, len(keys))
+		for i, key := range keys {
+			poolKeys[i] = keypool.Key{ID: keypool.Redact(key), Provider: build(key)}
+		}
+		logger.Info("pooled API keys", zap.String("provider", name), zap.Int("keys", len(keys)))
+		return keypool.New(name, poolKeys, keyPolicy)
+	}
+
 	// OpenRouter provider
-	if key := os.Getenv("OPENROUTER_API_KEY"); key != "" {
-		p := openrouter.New(&provider.Config{
-			APIKey:       key,
-			DefaultModel: "google/gemini-flash-1.5-8b", // Exact model ID
+	if keys := apiKeys("OPENROUTER"); len(keys) > 0 {
+		providers["openrouter"] = pooled("openrouter", keys, func(key string) provider.LLMProvider {
+			p := openrouter.New(&provider.Config{
+				APIKey:       key,
+				DefaultModel: "google/gemini-flash-1.5-8b", // Exact model ID
+				Transport:    transportFor("OPENROUTER"),
+			})
+			routing := openrouter.Routing{
+				Order:             splitList(os.Getenv("OPENROUTER_PROVIDER_ORDER")),
+				DataCollection:    os.Getenv("OPENROUTER_DATA_COLLECTION"),
+				RequireParameters: os.Getenv("OPENROUTER_REQUIRE_PARAMETERS") == "true",
+				Quantizations:     splitList(os.Getenv("OPENROUTER_QUANTIZATIONS")),
+				Models:            splitList(os.Getenv("OPENROUTER_FALLBACK_MODELS")),
+				Transforms:        splitList(os.Getenv("OPENROUTER_TRANSFORMS")),
+			}
+			if v := os.Getenv("OPENROUTER_ALLOW_FALLBACKS"); v != "" {
+				allow := v == "true"
+				routing.AllowFallbacks = &allow
+			}
+			p.WithRouting(routing)
+			if referer, ok := os.LookupEnv("OPENROUTER_HTTP_REFERER"); ok {
+				p.WithReferer(referer)
+			}
+			if title, ok := os.LookupEnv("OPENROUTER_APP_TITLE"); ok {
+				p.WithTitle(title)
+			}
+			if os.Getenv("OPENROUTER_GENERATION_LOOKUP") == "true" {
+				p.WithGenerationLookup()
+			}
+			return p
 		})
-		providers["openrouter"] = p
 		logger.Info("initialized OpenRouter provider")
 	}
 
 	// OpenAI provider
-	if key := os.Getenv("OPENAI_API_KEY"); key != "" {
-		p := openai.New(&provider.Config{
-			APIKey:       key,
-			DefaultModel: "gpt-3.5-turbo", // Default model for OpenAI
+	if keys := apiKeys("OPENAI"); len(keys) > 0 {
+		providers["openai"] = pooled("openai", keys, func(key string) provider.LLMProvider {
+			p := openai.New(&provider.Config{
+				APIKey:       key,
+				DefaultModel: "gpt-3.5-turbo", // Default model for OpenAI
+				Transport:    transportFor("OPENAI"),
+			})
+			if os.Getenv("OPENAI_RESPONSES_API") == "true" {
+				p.WithResponsesAPI()
+			}
+			return p
 		})
-		providers["openai"] = p
 		logger.Info("initialized OpenAI provider")
 	}
 
 	// Anthropic provider
-	if key := os.Getenv("ANTHROPIC_API_KEY"); key != "" {
-		p := anthropic.New(&provider.Config{
-			APIKey:       key,
-			DefaultModel: "claude-2", // Default model for Anthropic
+	if keys := apiKeys("ANTHROPIC"); len(keys) > 0 {
+		providers["anthropic"] = pooled("anthropic", keys, func(key string) provider.LLMProvider {
+			return anthropic.New(&provider.Config{
+				APIKey:       key,
+				DefaultModel: "claude-2", // Default model for Anthropic
+				Transport:    transportFor("ANTHROPIC"),
+			})
 		})
-		providers["anthropic"] = p
 		logger.Info("initialized Anthropic provider")
 	}
 
 	// Gemini provider
-	if key := os.Getenv("GEMINI_API_KEY"); key != "" {
-		p, err := gemini.New(&provider.Config{
-			APIKey:       key,
-			DefaultModel: "gemini-1.5-flash-8b", // Updated to match your model
+	if keys := apiKeys("GEMINI"); len(keys) > 0 {
+		providers["gemini"] = pooled("gemini", keys, func(key string) provider.LLMProvider {
+			p, err := gemini.New(&provider.Config{
+				APIKey:       key,
+				DefaultModel: "gemini-1.5-flash-8b", // Updated to match your model
+				Transport:    transportFor("GEMINI"),
+			})
+			if err != nil {
+				logger.Fatal("failed to initialize Gemini provider", zap.Error(err))
+			}
+			if spec := os.Getenv("GEMINI_SAFETY_SETTINGS"); spec != "" {
+				settings, err := gemini.ParseSafetySettings(spec)
+				if err != nil {
+					logger.Fatal("invalid GEMINI_SAFETY_SETTINGS", zap.Error(err))
+				}
+				p.WithSafetySettings(settings)
+			}
+			return p
 		})
-		if err != nil {
-			logger.Fatal("failed to initialize Gemini provider", zap.Error(err))
-		}
-		providers["gemini"] = p
 		logger.Info("initialized Gemini provider")
 	}
 
+	// Hugging Face Text Generation Inference provider (self-hosted, API key optional)
+	if baseURL := os.Getenv("TGI_BASE_URL"); baseURL != "" {
+		keys := apiKeys("TGI")
+		if len(keys) == 0 {
+			keys = []string{""}
+		}
+		providers["tgi"] = pooled("tgi", keys, func(key string) provider.LLMProvider {
+			p := tgi.New(&provider.Config{
+				APIKey:       key,
+				DefaultModel: os.Getenv("TGI_DEFAULT_MODEL"),
+				BaseURL:      baseURL,
+				Transport:    transportFor("TGI"),
+			})
+			if os.Getenv("TGI_MESSAGES_API") == "true" {
+				p.WithMessagesAPI()
+			}
+			return p
+		})
+		logger.Info("initialized TGI provider", zap.String("base_url", baseURL))
+	}
+
 	if len(providers) == 0 {
-		logger.Fatal("no providers initialized - please set at least one provider API key")
+		logger.Fatal("no providers initialized - please set at least one provider API key or TGI_BASE_URL")
+	}
+
+	// Retry transient provider failures (RETRY_MAX_ATTEMPTS=1 disables retries)
+	retryPolicy, err := retryPolicyFromEnv()
+	if err != nil {
+		logger.Fatal("invalid retry settings", zap.Error(err))
+	}
+	if retryPolicy.MaxAttempts > 1 {
+		for name, p := range providers {
+			providers[name] = retry.New(name, p, retryPolicy)
+		}
 	}
 
 	// Create gRPC server
 	grpcServer := grpc.NewServer()
 
 	// Register LLM service
-	llmServer := server.New(providers)
+	var serverOpts []server.Option
+	if os.Getenv("CIRCUIT_BREAKER_ENABLED") != "false" {
+		providerBreaker, modelBreaker, err := breakerConfigsFromEnv()
+		if err != nil {
+			logger.Fatal("invalid circuit breaker settings", zap.Error(err))
+		}
+		serverOpts = append(serverOpts, server.WithCircuitBreakers(providerBreaker, modelBreaker))
+	}
+	routes, err := routesFromEnv()
+	if err != nil {
+		logger.Fatal("invalid route settings", zap.Error(err))
+	}
+	if len(routes) > 0 {
+		serverOpts = append(serverOpts, server.WithRoutes(routes))
+	}
+	if os.Getenv("HEDGING_ENABLED") == "true" {
+		policy, err := hedgePolicyFromEnv()
+		if err != nil {
+			logger.Fatal("invalid hedging settings", zap.Error(err))
+		}
+		serverOpts = append(serverOpts, server.WithHedging(policy))
+	}
+	timeouts, routeTimeouts, err := timeoutsFromEnv()
+	if err != nil {
+		logger.Fatal("invalid timeout settings", zap.Error(err))
+	}
+	serverOpts = append(serverOpts, server.WithTimeouts(timeouts, routeTimeouts))
+	limits, err := limitsFromEnv()
+	if err != nil {
+		logger.Fatal("invalid concurrency limit settings", zap.Error(err))
+	}
+	if len(limits) > 0 {
+		serverOpts = append(serverOpts, server.WithConcurrencyLimits(limits))
+	}
+	if value := os.Getenv("STREAM_RETENTION"); value != "" {
+		retention, err := time.ParseDuration(value)
+		if err != nil {
+			logger.Fatal("invalid STREAM_RETENTION", zap.Error(err))
+		}
+		maxBytes := int64(64 << 20)
+		if value := os.Getenv("STREAM_BUFFER_MAX_BYTES"); value != "" {
+			if maxBytes, err = strconv.ParseInt(value, 10, 64); err != nil || maxBytes < 0 {
+				logger.Fatal("invalid STREAM_BUFFER_MAX_BYTES", zap.String("value", value))
+			}
+		}
+		if retention > 0 {
+			serverOpts = append(serverOpts, server.WithStreamResumption(retention, maxBytes))
+		}
+	}
+	llmServer := server.New(providers, serverOpts...)
 	pb.RegisterLLMServiceServer(grpcServer, llmServer)
 
 	// Register health check service
-	healthServer := server.NewHealthServer()
+	healthConfig, err := healthConfigFromEnv()
+	if err != nil {
+		logger.Fatal("invalid health settings", zap.Error(err))
+	}
+	healthServer := server.NewHealthServer().WithLLMServer(llmServer).WithConfig(healthConfig)
+	healthServer.Start()
 	grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
 
 	// Enable reflection for development tools
@@ -119,15 +281,56 @@ func main() {
 		logger.Fatal("failed to listen", zap.Error(err))
 	}
 
+	// The admin service can reset circuits and lists API keys, so it is only served on
+	// its own port, which should not be exposed beyond the operators' network
+	var adminServer *grpc.Server
+	if adminPort := os.Getenv("ADMIN_PORT"); adminPort != "" {
+		adminLis, err := net.Listen("tcp", fmt.Sprintf(":%s", adminPort))
+		if err != nil {
+			logger.Fatal("failed to listen on admin port", zap.Error(err))
+		}
+		adminServer = grpc.NewServer()
+		pb.RegisterAdminServiceServer(adminServer, server.NewAdminServer(llmServer))
+		reflection.Register(adminServer)
+		go func() {
+			logger.Info("starting admin gRPC server", zap.String("port", adminPort))
+			if err := adminServer.Serve(adminLis); err != nil {
+				logger.Fatal("failed to serve admin", zap.Error(err))
+			}
+		}()
+	}
+
+	gracePeriod := 25 * time.Second
+	if value := os.Getenv("SHUTDOWN_GRACE_PERIOD"); value != "" {
+		if gracePeriod, err = time.ParseDuration(value); err != nil {
+			logger.Fatal("invalid SHUTDOWN_GRACE_PERIOD", zap.Error(err))
+		}
+	}
+	drainDelay := 5 * time.Second
+	if value := os.Getenv("SHUTDOWN_DRAIN_DELAY"); value != "" {
+		if drainDelay, err = time.ParseDuration(value); err != nil {
+			logger.Fatal("invalid SHUTDOWN_DRAIN_DELAY", zap.Error(err))
+		}
+	}
+	if drainDelay >= gracePeriod {
+		logger.Fatal("SHUTDOWN_DRAIN_DELAY must be shorter than SHUTDOWN_GRACE_PERIOD")
+	}
+
 	// Handle graceful shutdown
+	drained := make(chan struct{})
 	go func() {
+		defer close(drained)
 		sigChan := make(chan os.Signal, 1)
 		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 		sig := <-sigChan
-		logger.Info("received shutdown signal", zap.String("signal", sig.String()))
-
-		// Gracefully stop the gRPC server
-		grpcServer.GracefulStop()
+		logger.Info("received shutdown signal, draining",
+			zap.String("signal", sig.String()),
+			zap.Duration("drain_delay", drainDelay),
+			zap.Duration("grace_period", gracePeriod))
+		if adminServer != nil {
+			adminServer.GracefulStop()
+		}
+		drain(grpcServer, llmServer, healthServer, drainDelay, gracePeriod, logger)
 	}()
 
 	// Start serving
@@ -135,4 +338,354 @@ func main() {
 	if err := grpcServer.Serve(lis); err != nil {
 		logger.Fatal("failed to serve", zap.Error(err))
 	}
+	<-drained
+}
+
+// drain stops the server without cutting off generations: health checks report
+// NOT_SERVING, and after the drain delay, which gives load balancers time to see that
+// and stop routing here, new RPCs are refused. In-flight RPCs have until the grace
+// period, counted from the start of the drain, to finish before the rest are canceled
+// with UNAVAILABLE.
+func drain(grpcServer *grpc.Server, llmServer *server.LLMServer, healthServer interface{ Shutdown() },
+	drainDelay, gracePeriod time.Duration, logger *zap.Logger) {
+	deadline := time.After(gracePeriod)
+	healthServer.Shutdown()
+	time.Sleep(drainDelay)
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		logger.Info("drained in-flight requests")
+		return
+	case <-deadline:
+	}
+
+	logger.Warn("grace period over, canceling in-flight requests")
+	llmServer.Shutdown()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		// Handlers that ignore cancellation are cut off with the connections
+		grpcServer.Stop()
+	}
+}
+
+// splitList parses a comma-separated environment value, ignoring empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// apiKeys returns the keys in <prefix>_API_KEYS, or else the single <prefix>_API_KEY
+func apiKeys(prefix string) []string {
+	if keys := splitList(os.Getenv(prefix + "_API_KEYS")); len(keys) > 0 {
+		return keys
+	}
+	if key := os.Getenv(prefix + "_API_KEY"); key != "" {
+		return []string{key}
+	}
+	return nil
+}
+
+// keyPoolPolicyFromEnv reads the KEY_* settings over the default key pool policy
+func keyPoolPolicyFromEnv() (keypool.Policy, error) {
+	policy := keypool.DefaultPolicy()
+
+	durations := map[string]*time.Duration{
+		"KEY_COOLDOWN":     &policy.Cooldown,
+		"KEY_MAX_COOLDOWN": &policy.MaxCooldown,
+	}
+	for name, target := range durations {
+		if value := os.Getenv(name); value != "" {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return policy, fmt.Errorf("invalid %s: %w", name, err)
+			}
+			*target = d
+		}
+	}
+
+	return policy, nil
+}
+
+// retryPolicyFromEnv reads the RETRY_* settings over the default retry policy
+func retryPolicyFromEnv() (retry.Policy, error) {
+	policy := retry.DefaultPolicy()
+
+	if value := os.Getenv("RETRY_MAX_ATTEMPTS"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return policy, fmt.Errorf("invalid RETRY_MAX_ATTEMPTS: %q", value)
+		}
+		policy.MaxAttempts = n
+	}
+
+	durations := map[string]*time.Duration{
+		"RETRY_INITIAL_BACKOFF": &policy.InitialBackoff,
+		"RETRY_MAX_BACKOFF":     &policy.MaxBackoff,
+		"RETRY_MAX_RETRY_AFTER": &policy.MaxRetryAfter,
+	}
+	for name, target := range durations {
+		if value := os.Getenv(name); value != "" {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return policy, fmt.Errorf("invalid %s: %w", name, err)
+			}
+			*target = d
+		}
+	}
+
+	return policy, nil
+}
+
+// breakerConfigsFromEnv reads the CIRCUIT_* settings for the provider-wide and per-model
+// circuit breakers. Provider circuits see the failures of every model, so they default
+// to twice the model threshold.
+func breakerConfigsFromEnv() (providerConfig, modelConfig server.BreakerConfig, err error) {
+	modelConfig = server.DefaultBreakerConfig()
+
+	ints := map[string]*int{
+		"CIRCUIT_FAILURE_THRESHOLD":  &modelConfig.FailureThreshold,
+		"CIRCUIT_HALF_OPEN_REQUESTS": &modelConfig.HalfOpenRequests,
+	}
+	for name, target := range ints {
+		if value := os.Getenv(name); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return providerConfig, modelConfig, fmt.Errorf("invalid %s: %q", name, value)
+			}
+			*target = n
+		}
+	}
+	if value := os.Getenv("CIRCUIT_OPEN_TIMEOUT"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return providerConfig, modelConfig, fmt.Errorf("invalid CIRCUIT_OPEN_TIMEOUT: %w", err)
+		}
+		modelConfig.OpenTimeout = d
+	}
+
+	providerConfig = modelConfig
+	providerConfig.FailureThreshold = 2 * modelConfig.FailureThreshold
+	if value := os.Getenv("CIRCUIT_PROVIDER_FAILURE_THRESHOLD"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return providerConfig, modelConfig, fmt.Errorf("invalid CIRCUIT_PROVIDER_FAILURE_THRESHOLD: %q", value)
+		}
+		providerConfig.FailureThreshold = n
+	}
+
+	return providerConfig, modelConfig, nil
+}
+
+// healthConfigFromEnv reads the HEALTH_* settings over the default health config
+func healthConfigFromEnv() (server.HealthConfig, error) {
+	config := server.DefaultHealthConfig()
+
+	if value := os.Getenv("HEALTH_ERROR_RATE"); value != "" {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate <= 0 || rate > 1 {
+			return config, fmt.Errorf("invalid HEALTH_ERROR_RATE: %q", value)
+		}
+		config.ErrorRate = rate
+	}
+	if value := os.Getenv("HEALTH_MIN_REQUESTS"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return config, fmt.Errorf("invalid HEALTH_MIN_REQUESTS: %q", value)
+		}
+		config.MinRequests = n
+	}
+
+	durations := map[string]*time.Duration{
+		"HEALTH_WINDOW":         &config.Window,
+		"HEALTH_PROBE_INTERVAL": &config.ProbeInterval,
+		"HEALTH_PROBE_TIMEOUT":  &config.ProbeTimeout,
+		"HEALTH_CHECK_INTERVAL": &config.CheckInterval,
+	}
+	for name, target := range durations {
+		if value := os.Getenv(name); value != "" {
+			d, err := time.ParseDuration(value)
+			// Only probes may be disabled
+			if err != nil || d < 0 || (d == 0 && name != "HEALTH_PROBE_INTERVAL") {
+				return config, fmt.Errorf("invalid %s: %q", name, value)
+			}
+			*target = d
+		}
+	}
+	return config, nil
+}
+
+// hedgePolicyFromEnv reads the HEDGE_* settings over the default hedging policy
+func hedgePolicyFromEnv() (server.HedgePolicy, error) {
+	policy := server.DefaultHedgePolicy()
+
+	if value := os.Getenv("HEDGE_DELAY"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return policy, fmt.Errorf("invalid HEDGE_DELAY: %w", err)
+		}
+		policy.Delay = d
+	}
+	if value := os.Getenv("HEDGE_PERCENTILE"); value != "" {
+		p, err := strconv.ParseFloat(value, 64)
+		if err != nil || p < 0 || p > 1 {
+			return policy, fmt.Errorf("invalid HEDGE_PERCENTILE: %q", value)
+		}
+		policy.Percentile = p
+	}
+	if value := os.Getenv("HEDGE_MIN_SAMPLES"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return policy, fmt.Errorf("invalid HEDGE_MIN_SAMPLES: %q", value)
+		}
+		policy.MinSamples = n
+	}
+
+	return policy, nil
+}
+
+// timeoutsFromEnv reads the default timeout policy from TIMEOUTS and per-route policies
+// from TIMEOUTS_<ROUTE> variables, each over the built-in defaults, e.g.
+// TIMEOUTS=idle=30s and TIMEOUTS_REASONING=first_token=15m,idle=5m
+func timeoutsFromEnv() (server.TimeoutPolicy, map[string]server.TimeoutPolicy, error) {
+	defaults, err := server.ParseTimeouts(os.Getenv("TIMEOUTS"), server.DefaultTimeoutPolicy())
+	if err != nil {
+		return defaults, nil, fmt.Errorf("invalid TIMEOUTS: %w", err)
+	}
+
+	routes := make(map[string]server.TimeoutPolicy)
+	for _, env := range os.Environ() {
+		name, spec, _ := strings.Cut(env, "=")
+		if !strings.HasPrefix(name, "TIMEOUTS_") || name == "TIMEOUTS_" {
+			continue
+		}
+		policy, err := server.ParseTimeouts(spec, defaults)
+		if err != nil {
+			return defaults, nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		routes[strings.ToLower(strings.TrimPrefix(name, "TIMEOUTS_"))] = policy
+	}
+	return defaults, routes, nil
+}
+
+// limitsFromEnv reads the CONCURRENCY_LIMITS spec and the QUEUE_* settings shared by
+// every limit
+func limitsFromEnv() (map[string]server.LimitConfig, error) {
+	spec := os.Getenv("CONCURRENCY_LIMITS")
+	if spec == "" {
+		return nil, nil
+	}
+
+	maxQueue := 100
+	if value := os.Getenv("QUEUE_MAX_SIZE"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid QUEUE_MAX_SIZE: %q", value)
+		}
+		maxQueue = n
+	}
+	maxWait := 30 * time.Second
+	if value := os.Getenv("QUEUE_MAX_WAIT"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid QUEUE_MAX_WAIT: %w", err)
+		}
+		maxWait = d
+	}
+
+	limits, err := server.ParseLimits(spec, maxQueue, maxWait)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CONCURRENCY_LIMITS: %w", err)
+	}
+	return limits, nil
+}
+
+// routesFromEnv reads named fallback chains from ROUTE_<NAME> variables; requests select
+// them by the lowercased name, e.g. ROUTE_FAST=anthropic/claude-3-5-haiku-latest,openai/gpt-4o-mini
+// is the route "fast"
+func routesFromEnv() (map[string][]server.Target, error) {
+	routes := make(map[string][]server.Target)
+	for _, env := range os.Environ() {
+		name, spec, _ := strings.Cut(env, "=")
+		if !strings.HasPrefix(name, "ROUTE_") || name == "ROUTE_" {
+			continue
+		}
+		route, err := server.ParseRoute(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		routes[strings.ToLower(strings.TrimPrefix(name, "ROUTE_"))] = route
+	}
+	return routes, nil
+}
+
+// transportFromEnv reads HTTP transport settings, preferring <prefix>_HTTP_* over HTTP_*
+func transportFromEnv(prefix string) (provider.TransportConfig, error) {
+	var transport provider.TransportConfig
+	lookup := func(name string) string {
+		if value := os.Getenv(prefix + "_HTTP_" + name); value != "" {
+			return value
+		}
+		return os.Getenv("HTTP_" + name)
+	}
+
+	durations := map[string]*time.Duration{
+		"CONNECT_TIMEOUT":         &transport.ConnectTimeout,
+		"TLS_HANDSHAKE_TIMEOUT":   &transport.TLSHandshakeTimeout,
+		"RESPONSE_HEADER_TIMEOUT": &transport.ResponseHeaderTimeout,
+		"IDLE_CONN_TIMEOUT":       &transport.IdleConnTimeout,
+		"REQUEST_TIMEOUT":         &transport.RequestTimeout,
+		"STREAM_TIMEOUT":          &transport.StreamTimeout,
+	}
+	for name, target := range durations {
+		if value := lookup(name); value != "" {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return transport, fmt.Errorf("invalid HTTP_%s: %w", name, err)
+			}
+			*target = d
+		}
+	}
+
+	ints := map[string]*int{
+		"MAX_IDLE_CONNS":          &transport.MaxIdleConns,
+		"MAX_IDLE_CONNS_PER_HOST": &transport.MaxIdleConnsPerHost,
+		"MAX_CONNS_PER_HOST":      &transport.MaxConnsPerHost,
+	}
+	for name, target := range ints {
+		if value := lookup(name); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return transport, fmt.Errorf("invalid HTTP_%s: %w", name, err)
+			}
+			*target = n
+		}
+	}
+
+	if value := lookup("PROXY_URL"); value != "" {
+		proxyURL, err := url.Parse(value)
+		if err != nil {
+			return transport, fmt.Errorf("invalid HTTP_PROXY_URL: %w", err)
+		}
+		transport.Proxy = proxyURL
+	}
+
+	tlsConfig, err := provider.LoadTLSConfig(lookup("CA_BUNDLE"), lookup("CLIENT_CERT"), lookup("CLIENT_KEY"))
+	if err != nil {
+		return transport, err
+	}
+	transport.TLS = tlsConfig
+
+	return transport, nil
 }
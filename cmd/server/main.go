@@ -11,6 +11,7 @@ import (
 	"github.com/c0rtexR/llm_service/internal/provider/gemini"
 	"github.com/c0rtexR/llm_service/internal/provider/openai"
 	"github.com/c0rtexR/llm_service/internal/provider/openrouter"
+	"github.com/c0rtexR/llm_service/internal/provider/tgi"
 	"github.com/c0rtexR/llm_service/internal/server"
 	pb "github.com/c0rtexR/llm_service/proto"
 
@@ -54,10 +55,25 @@ func main() {
 		if err != nil {
 			log.Printf("failed to initialize Gemini provider: %v", err)
 		} else {
+			if spec := os.Getenv("GEMINI_SAFETY_SETTINGS"); spec != "" {
+				settings, err := gemini.ParseSafetySettings(spec)
+				if err != nil {
+					log.Fatalf("invalid GEMINI_SAFETY_SETTINGS: %v", err)
+				}
+				p.WithSafetySettings(settings)
+			}
 			providers["gemini"] = p
 		}
 	}
 
+	// Initialize TGI provider if a base URL is set
+	if baseURL := os.Getenv("TGI_BASE_URL"); baseURL != "" {
+		providers["tgi"] = tgi.New(&provider.Config{
+			APIKey:  os.Getenv("TGI_API_KEY"),
+			BaseURL: baseURL,
+		})
+	}
+
 	s := grpc.NewServer()
 	pb.RegisterLLMServiceServer(s, server.New(providers))
 
@@ -0,0 +1,298 @@
+package anthropic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+// customIDPattern is the format Anthropic accepts for batch custom IDs
+var customIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// batchRequestBody represents the JSON structure for creating a message batch
+type batchRequestBody struct {
+	Requests []batchRequest `json:"requests"`
+}
+
+// batchRequest represents a single request within a message batch
+type batchRequest struct {
+	CustomID string      `json:"custom_id"`
+	Params   requestBody `json:"params"`
+}
+
+// batchResponseBody represents the JSON structure for a message batch
+type batchResponseBody struct {
+	ID               string `json:"id"`
+	Type             string `json:"type"`
+	ProcessingStatus string `json:"processing_status"`
+	RequestCounts    struct {
+		Processing int32 `json:"processing"`
+		Succeeded  int32 `json:"succeeded"`
+		Errored    int32 `json:"errored"`
+		Canceled   int32 `json:"canceled"`
+		Expired    int32 `json:"expired"`
+	} `json:"request_counts"`
+	CreatedAt  *time.Time `json:"created_at"`
+	EndedAt    *time.Time `json:"ended_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	ResultsURL string     `json:"results_url"`
+}
+
+// batchResultLine represents a single line in the batch results JSONL file
+type batchResultLine struct {
+	CustomID string `json:"custom_id"`
+	Result   struct {
+		Type    string        `json:"type"`
+		Message *responseBody `json:"message,omitempty"`
+		Error   *struct {
+			Type  string `json:"type"`
+			Error struct {
+				Type    string `json:"type"`
+				Message string `json:"message"`
+			} `json:"error"`
+		} `json:"error,omitempty"`
+	} `json:"result"`
+}
+
+// CreateBatch submits a set of requests to the Message Batches API
+func (p *Provider) CreateBatch(ctx context.Context, items []*pb.BatchRequestItem) (*pb.BatchJob, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("batch must contain at least one request")
+	}
+
+	body := batchRequestBody{
+		Requests: make([]batchRequest, 0, len(items)),
+	}
+	seen := make(map[string]bool, len(items))
+	// One request with a 1 hour cache breakpoint enables the beta for the batch
+	var beta string
+	for _, item := range items {
+		if !customIDPattern.MatchString(item.CustomId) {
+			return nil, fmt.Errorf("invalid custom_id %q: must match %s", item.CustomId, customIDPattern)
+		}
+		if seen[item.CustomId] {
+			return nil, fmt.Errorf("duplicate custom_id %q", item.CustomId)
+		}
+		seen[item.CustomId] = true
+
+		if item.Request == nil {
+			return nil, fmt.Errorf("request %q is empty", item.CustomId)
+		}
+		params, err := p.buildRequestBody(item.Request)
+		if err != nil {
+			return nil, fmt.Errorf("request %q: %w", item.CustomId, err)
+		}
+		if params.longCache() {
+			beta = extendedCacheTTLBeta
+		}
+		body.Requests = append(body.Requests, batchRequest{
+			CustomID: item.CustomId,
+			Params:   params,
+		})
+	}
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	respBody, err := p.doBatchRequest(ctx, "POST", fmt.Sprintf("%s/messages/batches", p.config.BaseURL), bytes.NewReader(jsonBody), beta)
+	if err != nil {
+		return nil, err
+	}
+	defer respBody.Close()
+
+	return decodeBatch(respBody)
+}
+
+// GetBatch returns the current status of a message batch
+func (p *Provider) GetBatch(ctx context.Context, batchID string) (*pb.BatchJob, error) {
+	batch, err := p.retrieveBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	return convertBatch(batch), nil
+}
+
+// BatchResults streams the results of an ended message batch
+func (p *Provider) BatchResults(ctx context.Context, batchID string) (<-chan *pb.BatchResult, <-chan error) {
+	resultChan := make(chan *pb.BatchResult)
+	errorChan := make(chan error, 1)
+
+	go func() {
+		defer close(resultChan)
+		defer close(errorChan)
+
+		batch, err := p.retrieveBatch(ctx, batchID)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		if batch.ProcessingStatus != "ended" {
+			errorChan <- fmt.Errorf("batch %s has not ended (status: %s)", batchID, batch.ProcessingStatus)
+			return
+		}
+
+		resultsURL := batch.ResultsURL
+		if resultsURL == "" {
+			resultsURL = fmt.Sprintf("%s/messages/batches/%s/results", p.config.BaseURL, url.PathEscape(batchID))
+		}
+
+		respBody, err := p.doBatchRequest(ctx, "GET", resultsURL, nil, "")
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		defer respBody.Close()
+
+		// Results are JSONL; a decoder reads one object per line without a line-length limit
+		decoder := json.NewDecoder(respBody)
+		for {
+			var line batchResultLine
+			if err := decoder.Decode(&line); err != nil {
+				if err == io.EOF {
+					return
+				}
+				errorChan <- fmt.Errorf("failed to parse batch result: %w", err)
+				return
+			}
+
+			select {
+			case resultChan <- convertBatchResult(&line):
+			case <-ctx.Done():
+				errorChan <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return resultChan, errorChan
+}
+
+// retrieveBatch fetches the raw batch object
+func (p *Provider) retrieveBatch(ctx context.Context, batchID string) (*batchResponseBody, error) {
+	if batchID == "" {
+		return nil, fmt.Errorf("batch ID is required")
+	}
+	// The ID is escaped into the path, so it cannot reach another endpoint
+	if batchID == "." || batchID == ".." {
+		return nil, fmt.Errorf("invalid batch ID %q", batchID)
+	}
+
+	respBody, err := p.doBatchRequest(ctx, "GET", fmt.Sprintf("%s/messages/batches/%s", p.config.BaseURL, url.PathEscape(batchID)), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	defer respBody.Close()
+
+	var batch batchResponseBody
+	if err := json.NewDecoder(respBody).Decode(&batch); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &batch, nil
+}
+
+// doBatchRequest sends an authenticated request to the Message Batches API, enabling
+// a beta unless it is empty
+func (p *Provider) doBatchRequest(ctx context.Context, method, url string, body io.Reader, beta string) (io.ReadCloser, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("x-api-key", p.config.APIKey)
+	httpReq.Header.Set("anthropic-version", apiVersion)
+	if beta != "" {
+		httpReq.Header.Set("anthropic-beta", beta)
+	}
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, provider.NewAPIError(resp, respBody)
+	}
+
+	return resp.Body, nil
+}
+
+// decodeBatch parses a batch object and converts it to the proto format
+func decodeBatch(r io.Reader) (*pb.BatchJob, error) {
+	var batch batchResponseBody
+	if err := json.NewDecoder(r).Decode(&batch); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return convertBatch(&batch), nil
+}
+
+// convertBatch converts an Anthropic batch object to the proto format
+func convertBatch(batch *batchResponseBody) *pb.BatchJob {
+	job := &pb.BatchJob{
+		Id:       batch.ID,
+		Provider: "anthropic",
+		RequestCounts: &pb.BatchRequestCounts{
+			Processing: batch.RequestCounts.Processing,
+			Succeeded:  batch.RequestCounts.Succeeded,
+			Errored:    batch.RequestCounts.Errored,
+			Canceled:   batch.RequestCounts.Canceled,
+			Expired:    batch.RequestCounts.Expired,
+		},
+		CreatedAt: unixSeconds(batch.CreatedAt),
+		EndedAt:   unixSeconds(batch.EndedAt),
+		ExpiresAt: unixSeconds(batch.ExpiresAt),
+	}
+
+	switch batch.ProcessingStatus {
+	case "in_progress":
+		job.Status = pb.BatchStatus_BATCH_STATUS_IN_PROGRESS
+	case "canceling":
+		job.Status = pb.BatchStatus_BATCH_STATUS_CANCELING
+	case "ended":
+		job.Status = pb.BatchStatus_BATCH_STATUS_ENDED
+	}
+
+	return job
+}
+
+// convertBatchResult converts a single results line to the proto format
+func convertBatchResult(line *batchResultLine) *pb.BatchResult {
+	result := &pb.BatchResult{
+		CustomId: line.CustomID,
+		Status:   line.Result.Type,
+	}
+
+	switch {
+	case line.Result.Type == "succeeded" && line.Result.Message != nil:
+		result.Response = convertResponse(line.Result.Message)
+	case line.Result.Error != nil:
+		result.Error = fmt.Sprintf("%s: %s", line.Result.Error.Error.Type, line.Result.Error.Error.Message)
+	case line.Result.Type != "succeeded":
+		result.Error = fmt.Sprintf("request %s", line.Result.Type)
+	}
+
+	return result
+}
+
+// unixSeconds converts an optional timestamp to Unix seconds
+func unixSeconds(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.Unix()
+}
@@ -0,0 +1,170 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+func TestCreateBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "POST", r.Method)
+		require.Equal(t, "/messages/batches", r.URL.Path)
+		require.Equal(t, "test-key", r.Header.Get("x-api-key"))
+		require.Equal(t, apiVersion, r.Header.Get("anthropic-version"))
+
+		var reqBody batchRequestBody
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		require.Len(t, reqBody.Requests, 2)
+		require.Equal(t, "req-1", reqBody.Requests[0].CustomID)
+		require.Equal(t, "test-model", reqBody.Requests[0].Params.Model)
+		require.Len(t, reqBody.Requests[0].Params.System, 1)
+		require.Equal(t, "Be brief", reqBody.Requests[0].Params.System[0].Text)
+		require.Equal(t, "Hello", reqBody.Requests[0].Params.Messages[0].Content)
+		require.False(t, reqBody.Requests[0].Params.Stream)
+		require.Equal(t, defaultMaxTokens, *reqBody.Requests[1].Params.MaxTokens)
+
+		fmt.Fprint(w, `{
+			"id": "msgbatch_123",
+			"type": "message_batch",
+			"processing_status": "in_progress",
+			"request_counts": {"processing": 2, "succeeded": 0, "errored": 0, "canceled": 0, "expired": 0},
+			"created_at": "2024-09-24T18:37:24.100435Z",
+			"ended_at": null,
+			"expires_at": "2024-09-25T18:37:24.100435Z",
+			"results_url": null
+		}`)
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("test-key", "test-model").WithBaseURL(server.URL))
+	job, err := p.CreateBatch(context.Background(), []*pb.BatchRequestItem{
+		{
+			CustomId: "req-1",
+			Request: &pb.LLMRequest{Messages: []*pb.ChatMessage{
+				{Role: "system", Content: "Be brief"},
+				{Role: "user", Content: "Hello"},
+			}},
+		},
+		{
+			CustomId: "req-2",
+			Request:  &pb.LLMRequest{Messages: []*pb.ChatMessage{{Role: "user", Content: "Bye"}}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "msgbatch_123", job.Id)
+	require.Equal(t, "anthropic", job.Provider)
+	require.Equal(t, pb.BatchStatus_BATCH_STATUS_IN_PROGRESS, job.Status)
+	require.Equal(t, int32(2), job.RequestCounts.Processing)
+	require.Equal(t, int64(1727203044), job.CreatedAt)
+	require.Zero(t, job.EndedAt)
+}
+
+func TestCreateBatchValidation(t *testing.T) {
+	p := New(provider.NewConfig("test-key", "test-model"))
+	req := &pb.LLMRequest{Messages: []*pb.ChatMessage{{Role: "user", Content: "Hello"}}}
+
+	_, err := p.CreateBatch(context.Background(), nil)
+	require.ErrorContains(t, err, "at least one request")
+
+	_, err = p.CreateBatch(context.Background(), []*pb.BatchRequestItem{{CustomId: "has space", Request: req}})
+	require.ErrorContains(t, err, "invalid custom_id")
+
+	_, err = p.CreateBatch(context.Background(), []*pb.BatchRequestItem{
+		{CustomId: "a", Request: req},
+		{CustomId: "a", Request: req},
+	})
+	require.ErrorContains(t, err, "duplicate custom_id")
+}
+
+func TestBatchResults(t *testing.T) {
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/messages/batches/msgbatch_123":
+			fmt.Fprintf(w, `{
+				"id": "msgbatch_123",
+				"type": "message_batch",
+				"processing_status": "ended",
+				"request_counts": {"processing": 0, "succeeded": 1, "errored": 1, "canceled": 0, "expired": 0},
+				"created_at": "2024-09-24T18:37:24.100435Z",
+				"ended_at": "2024-09-24T18:40:00Z",
+				"results_url": "%s/messages/batches/msgbatch_123/results"
+			}`, serverURL)
+		case "/messages/batches/msgbatch_123/results":
+			fmt.Fprintln(w, `{"custom_id":"req-1","result":{"type":"succeeded","message":{"id":"msg_1","type":"message","role":"assistant","model":"claude-3-5-haiku-latest","content":[{"type":"text","text":"Hi!"}],"stop_reason":"end_turn","usage":{"input_tokens":10,"output_tokens":3}}}}`)
+			fmt.Fprintln(w, `{"custom_id":"req-2","result":{"type":"errored","error":{"type":"error","error":{"type":"invalid_request_error","message":"max_tokens: too large"}}}}`)
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	p := New(provider.NewConfig("test-key", "test-model").WithBaseURL(server.URL))
+
+	job, err := p.GetBatch(context.Background(), "msgbatch_123")
+	require.NoError(t, err)
+	require.Equal(t, pb.BatchStatus_BATCH_STATUS_ENDED, job.Status)
+	require.Equal(t, int32(1), job.RequestCounts.Errored)
+
+	resultChan, errChan := p.BatchResults(context.Background(), "msgbatch_123")
+	var results []*pb.BatchResult
+	for result := range resultChan {
+		results = append(results, result)
+	}
+	require.NoError(t, <-errChan)
+
+	require.Len(t, results, 2)
+	require.Equal(t, "req-1", results[0].CustomId)
+	require.Equal(t, "succeeded", results[0].Status)
+	require.Equal(t, "Hi!", results[0].Response.Content)
+	require.Equal(t, int32(13), results[0].Response.Usage.TotalTokens)
+	require.Equal(t, "req-2", results[1].CustomId)
+	require.Equal(t, "errored", results[1].Status)
+	require.Nil(t, results[1].Response)
+	require.Equal(t, "invalid_request_error: max_tokens: too large", results[1].Error)
+}
+
+func TestBatchResultsNotEnded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"msgbatch_123","type":"message_batch","processing_status":"in_progress"}`)
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("test-key", "test-model").WithBaseURL(server.URL))
+	resultChan, errChan := p.BatchResults(context.Background(), "msgbatch_123")
+
+	err := <-errChan
+	require.ErrorContains(t, err, "has not ended")
+	_, ok := <-resultChan
+	require.False(t, ok)
+}
+
+func TestGetBatchEscapesID(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		fmt.Fprint(w, `{"id":"msgbatch_123","type":"message_batch","processing_status":"in_progress"}`)
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("test-key", "test-model").WithBaseURL(server.URL))
+
+	// A client's batch ID cannot leave the batches endpoint
+	_, err := p.GetBatch(context.Background(), "../../models")
+	require.NoError(t, err)
+	require.Equal(t, []string{"/messages/batches/..%2F..%2Fmodels"}, paths)
+
+	_, err = p.GetBatch(context.Background(), "..")
+	require.ErrorContains(t, err, "invalid batch ID")
+	require.Len(t, paths, 1)
+}
@@ -1,16 +1,15 @@
 package anthropic
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
 
 	"github.com/c0rtexR/llm_service/internal/provider"
+	"github.com/c0rtexR/llm_service/internal/sse"
 	pb "github.com/c0rtexR/llm_service/proto"
 )
 
@@ -22,10 +21,20 @@ const (
 
 var defaultMaxTokens int32 = 1024 // Default max tokens if not specified
 
+// maxCacheBreakpoints is the number of cache_control blocks Anthropic accepts per request
+const maxCacheBreakpoints = 4
+
+// defaultCacheTTL is the lifetime in seconds of Anthropic's default ephemeral cache
+const defaultCacheTTL = 300
+
+// extendedCacheTTLBeta is the beta that enables 1 hour cache breakpoints
+const extendedCacheTTLBeta = "extended-cache-ttl-2025-04-11"
+
 // Provider implements the LLMProvider interface for Anthropic
 type Provider struct {
-	config     *provider.Config
-	httpClient *http.Client
+	config       *provider.Config
+	httpClient   *http.Client
+	streamClient *http.Client
 }
 
 // requestBody represents the JSON structure for Anthropic API requests
@@ -46,15 +55,68 @@ type systemMessage struct {
 	CacheControl *cacheConfig `json:"cache_control,omitempty"`
 }
 
-// chatMessage represents a single message in the Anthropic format
+// chatMessage represents a single message in the Anthropic format.
+// Messages with a cache breakpoint are sent as a text block carrying cache_control,
+// all others use the plain string content form.
 type chatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role         string
+	Content      string
+	CacheControl *cacheConfig
+}
+
+// textBlock represents a text content block in a request message
+type textBlock struct {
+	Type         string       `json:"type"`
+	Text         string       `json:"text"`
+	CacheControl *cacheConfig `json:"cache_control,omitempty"`
+}
+
+// MarshalJSON encodes the message using string content unless it carries a cache breakpoint
+func (m chatMessage) MarshalJSON() ([]byte, error) {
+	if m.CacheControl == nil {
+		return json.Marshal(struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		}{m.Role, m.Content})
+	}
+	return json.Marshal(struct {
+		Role    string      `json:"role"`
+		Content []textBlock `json:"content"`
+	}{m.Role, []textBlock{{Type: "text", Text: m.Content, CacheControl: m.CacheControl}}})
+}
+
+// UnmarshalJSON decodes either the string or the content block form of a message
+func (m *chatMessage) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Role    string          `json:"role"`
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	m.Role = raw.Role
+
+	if err := json.Unmarshal(raw.Content, &m.Content); err == nil {
+		return nil
+	}
+	var blocks []textBlock
+	if err := json.Unmarshal(raw.Content, &blocks); err != nil {
+		return err
+	}
+	m.Content = ""
+	for _, block := range blocks {
+		m.Content += block.Text
+		if block.CacheControl != nil {
+			m.CacheControl = block.CacheControl
+		}
+	}
+	return nil
 }
 
 // cacheConfig represents Anthropic's cache control settings
 type cacheConfig struct {
 	Type string `json:"type"`
+	TTL  string `json:"ttl,omitempty"`
 }
 
 // responseBody represents the JSON structure for Anthropic API responses
@@ -65,12 +127,22 @@ type responseBody struct {
 	Model      string         `json:"model"`
 	Content    []contentBlock `json:"content"`
 	StopReason string         `json:"stop_reason"`
-	Usage      struct {
-		InputTokens              int32 `json:"input_tokens"`
-		OutputTokens             int32 `json:"output_tokens"`
-		CacheReadInputTokens     int32 `json:"cache_read_input_tokens,omitempty"`
-		CacheCreationInputTokens int32 `json:"cache_creation_input_tokens,omitempty"`
-	} `json:"usage"`
+	Usage      usageBody      `json:"usage"`
+}
+
+// usageBody represents token usage reported by the Anthropic API
+type usageBody struct {
+	InputTokens              int32 `json:"input_tokens"`
+	OutputTokens             int32 `json:"output_tokens"`
+	CacheReadInputTokens     int32 `json:"cache_read_input_tokens,omitempty"`
+	CacheCreationInputTokens int32 `json:"cache_creation_input_tokens,omitempty"`
+}
+
+// promptTokens returns the whole prompt's token count. Anthropic's input_tokens only
+// counts the tokens after the last cache breakpoint, so cache reads and writes are
+// added back to match the other providers' prompt counts.
+func (u usageBody) promptTokens() int32 {
+	return u.InputTokens + u.CacheReadInputTokens + u.CacheCreationInputTokens
 }
 
 // contentBlock represents a single content block in the response
@@ -79,39 +151,47 @@ type contentBlock struct {
 	Text string `json:"text"`
 }
 
-// streamResponseBody represents a single chunk in the SSE stream
-type streamResponseBody struct {
-	Type    string         `json:"type"`
-	Content []contentBlock `json:"content,omitempty"`
-	Delta   struct {
+// streamEvent represents a single event in the Messages SSE stream
+type streamEvent struct {
+	Type    string        `json:"type"`
+	Index   int           `json:"index"`
+	Message *responseBody `json:"message,omitempty"`
+	Delta   *struct {
 		Type         string `json:"type"`
 		Text         string `json:"text,omitempty"`
-		TextDelta    string `json:"text_delta,omitempty"`
+		Thinking     string `json:"thinking,omitempty"`
 		StopReason   string `json:"stop_reason,omitempty"`
 		StopSequence string `json:"stop_sequence,omitempty"`
 	} `json:"delta,omitempty"`
-	Message struct {
-		ID           string         `json:"id"`
-		Type         string         `json:"type"`
-		Role         string         `json:"role"`
-		Model        string         `json:"model"`
-		Content      []contentBlock `json:"content"`
-		StopReason   string         `json:"stop_reason"`
-		StopSequence string         `json:"stop_sequence"`
-		Usage        struct {
-			InputTokens              int32 `json:"input_tokens"`
-			OutputTokens             int32 `json:"output_tokens"`
-			CacheReadInputTokens     int32 `json:"cache_read_input_tokens,omitempty"`
-			CacheCreationInputTokens int32 `json:"cache_creation_input_tokens,omitempty"`
-		} `json:"usage"`
-	} `json:"message,omitempty"`
-	StopReason string `json:"stop_reason,omitempty"`
-	Usage      *struct {
-		InputTokens              int32 `json:"input_tokens"`
-		OutputTokens             int32 `json:"output_tokens"`
-		CacheReadInputTokens     int32 `json:"cache_read_input_tokens,omitempty"`
-		CacheCreationInputTokens int32 `json:"cache_creation_input_tokens,omitempty"`
-	} `json:"usage,omitempty"`
+	Usage *usageBody       `json:"usage,omitempty"`
+	Error *streamErrorBody `json:"error,omitempty"`
+}
+
+// streamErrorBody represents the error object in an SSE error event
+type streamErrorBody struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// StreamError is returned when Anthropic sends an error event mid-stream,
+// such as overloaded_error or api_error
+type StreamError struct {
+	Type    string
+	Message string
+}
+
+// Error implements the error interface
+func (e *StreamError) Error() string {
+	return fmt.Sprintf("anthropic stream error (%s): %s", e.Type, e.Message)
+}
+
+// Temporary reports whether the error is transient, so the request may be retried
+func (e *StreamError) Temporary() bool {
+	switch e.Type {
+	case "overloaded_error", "api_error", "rate_limit_error":
+		return true
+	}
+	return false
 }
 
 // New creates a new Anthropic provider instance
@@ -124,68 +204,17 @@ func New(config *provider.Config) *Provider {
 	}
 
 	return &Provider{
-		config:     config,
-		httpClient: &http.Client{},
+		config:       config,
+		httpClient:   config.NewHTTPClient(),
+		streamClient: config.NewStreamingHTTPClient(),
 	}
 }
 
 // Invoke implements the LLMProvider interface for synchronous requests
 func (p *Provider) Invoke(ctx context.Context, req *pb.LLMRequest) (*pb.LLMResponse, error) {
-	// Use model from request or fall back to default
-	model := req.Model
-	if model == "" {
-		model = p.config.DefaultModel
-	}
-
-	// Convert messages to Anthropic format
-	messages := make([]chatMessage, 0, len(req.Messages))
-	var systemMessages []systemMessage
-
-	for _, msg := range req.Messages {
-		// Extract system message if present
-		if msg.Role == "system" {
-			sysMsg := systemMessage{
-				Type: "text",
-				Text: msg.Content,
-			}
-			// Add cache control if requested
-			if req.CacheControl != nil && req.CacheControl.UseCache {
-				sysMsg.CacheControl = &cacheConfig{
-					Type: "ephemeral",
-				}
-			}
-			systemMessages = append(systemMessages, sysMsg)
-			continue
-		}
-
-		// Create chat message
-		chatMsg := chatMessage{
-			Role:    msg.Role,
-			Content: msg.Content,
-		}
-
-		messages = append(messages, chatMsg)
-	}
-
-	// Prepare request body
-	body := requestBody{
-		Model:    model,
-		Messages: messages,
-		System:   systemMessages,
-	}
-
-	// Add optional parameters if provided
-	if req.Temperature != 0 {
-		body.Temperature = &req.Temperature
-	}
-	if req.MaxTokens != 0 {
-		tokens := int32(req.MaxTokens)
-		body.MaxTokens = &tokens
-	} else {
-		body.MaxTokens = &defaultMaxTokens
-	}
-	if req.TopP != 0 {
-		body.TopP = &req.TopP
+	body, err := p.buildRequestBody(req)
+	if err != nil {
+		return nil, err
 	}
 
 	// Marshal request body
@@ -206,6 +235,9 @@ func (p *Provider) Invoke(ctx context.Context, req *pb.LLMRequest) (*pb.LLMRespo
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("x-api-key", p.config.APIKey)
 	httpReq.Header.Set("anthropic-version", apiVersion)
+	if body.longCache() {
+		httpReq.Header.Set("anthropic-beta", extendedCacheTTLBeta)
+	}
 
 	// Send request
 	resp, err := p.httpClient.Do(httpReq)
@@ -222,7 +254,7 @@ func (p *Provider) Invoke(ctx context.Context, req *pb.LLMRequest) (*pb.LLMRespo
 
 	// Check for error response
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, respBody)
+		return nil, provider.NewAPIError(resp, respBody)
 	}
 
 	// Parse response
@@ -231,22 +263,7 @@ func (p *Provider) Invoke(ctx context.Context, req *pb.LLMRequest) (*pb.LLMRespo
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	// Convert to proto response
-	var content string
-	for _, block := range response.Content {
-		if block.Type == "text" {
-			content += block.Text
-		}
-	}
-
-	return &pb.LLMResponse{
-		Content: content,
-		Usage: &pb.UsageInfo{
-			PromptTokens:     response.Usage.InputTokens,
-			CompletionTokens: response.Usage.OutputTokens,
-			TotalTokens:      response.Usage.InputTokens + response.Usage.OutputTokens,
-		},
-	}, nil
+	return convertResponse(&response), nil
 }
 
 // InvokeStream implements the LLMProvider interface for streaming requests
@@ -258,63 +275,13 @@ func (p *Provider) InvokeStream(ctx context.Context, req *pb.LLMRequest) (<-chan
 		defer close(responseChan)
 		defer close(errorChan)
 
-		// Use model from request or fall back to default
-		model := req.Model
-		if model == "" {
-			model = p.config.DefaultModel
-		}
-
-		// Convert messages to Anthropic format
-		messages := make([]chatMessage, 0, len(req.Messages))
-		var systemMessages []systemMessage
-
-		for _, msg := range req.Messages {
-			// Extract system message if present
-			if msg.Role == "system" {
-				sysMsg := systemMessage{
-					Type: "text",
-					Text: msg.Content,
-				}
-				// Add cache control if requested
-				if req.CacheControl != nil && req.CacheControl.UseCache {
-					sysMsg.CacheControl = &cacheConfig{
-						Type: "ephemeral",
-					}
-				}
-				systemMessages = append(systemMessages, sysMsg)
-				continue
-			}
-
-			// Create chat message
-			chatMsg := chatMessage{
-				Role:    msg.Role,
-				Content: msg.Content,
-			}
-
-			messages = append(messages, chatMsg)
-		}
-
 		// Prepare request body
-		body := requestBody{
-			Model:    model,
-			Messages: messages,
-			System:   systemMessages,
-			Stream:   true,
-		}
-
-		// Add optional parameters if provided
-		if req.Temperature != 0 {
-			body.Temperature = &req.Temperature
-		}
-		if req.MaxTokens != 0 {
-			tokens := int32(req.MaxTokens)
-			body.MaxTokens = &tokens
-		} else {
-			body.MaxTokens = &defaultMaxTokens
-		}
-		if req.TopP != 0 {
-			body.TopP = &req.TopP
+		body, err := p.buildRequestBody(req)
+		if err != nil {
+			errorChan <- err
+			return
 		}
+		body.Stream = true
 
 		// Marshal request body
 		jsonBody, err := json.Marshal(body)
@@ -337,9 +304,12 @@ func (p *Provider) InvokeStream(ctx context.Context, req *pb.LLMRequest) (<-chan
 		httpReq.Header.Set("x-api-key", p.config.APIKey)
 		httpReq.Header.Set("anthropic-version", apiVersion)
 		httpReq.Header.Set("Accept", "text/event-stream")
+		if body.longCache() {
+			httpReq.Header.Set("anthropic-beta", extendedCacheTTLBeta)
+		}
 
 		// Send request
-		resp, err := p.httpClient.Do(httpReq)
+		resp, err := p.streamClient.Do(httpReq)
 		if err != nil {
 			errorChan <- fmt.Errorf("failed to send request: %w", err)
 			return
@@ -349,109 +319,318 @@ func (p *Provider) InvokeStream(ctx context.Context, req *pb.LLMRequest) (<-chan
 		// Check for error response
 		if resp.StatusCode != http.StatusOK {
 			body, _ := io.ReadAll(resp.Body)
-			errorChan <- fmt.Errorf("request failed with status %d: %s", resp.StatusCode, body)
+			errorChan <- provider.NewAPIError(resp, body)
 			return
 		}
 
-		// Create scanner for SSE stream
-		scanner := bufio.NewScanner(resp.Body)
-		var usage *pb.UsageInfo
+		if err := consumeStream(ctx, resp.Body, responseChan); err != nil {
+			errorChan <- err
+		}
+	}()
 
-		// Read stream
-		for scanner.Scan() {
-			line := scanner.Text()
-			if line == "" {
-				continue
+	return responseChan, errorChan
+}
+
+// consumeStream follows the Messages streaming lifecycle: message_start carries the
+// prompt usage, content_block_delta events carry text, message_delta carries the stop
+// reason and cumulative output usage, and message_stop ends the stream. Usage is only
+// reported as Anthropic sends it; nothing is estimated.
+func consumeStream(ctx context.Context, body io.Reader, responseChan chan<- *pb.LLMStreamResponse) error {
+	decoder := sse.NewDecoder(body)
+
+	send := func(resp *pb.LLMStreamResponse) error {
+		select {
+		case responseChan <- resp:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	var (
+		messageID    string
+		usage        pb.UsageInfo
+		stopReason   string
+		sawStart     bool
+		sawUsageInfo bool
+	)
+
+	for {
+		sseEvent, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error reading stream: %w", err)
+		}
+
+		// Event names are repeated in the JSON "type" field, so only the data matters
+		var event streamEvent
+		if err := json.Unmarshal([]byte(sseEvent.Data), &event); err != nil {
+			return fmt.Errorf("failed to parse SSE data: %w", err)
+		}
+
+		switch event.Type {
+		case "message_start":
+			sawStart = true
+			if event.Message != nil {
+				messageID = event.Message.ID
+				usage.PromptTokens = event.Message.Usage.InputTokens
+				usage.CompletionTokens = event.Message.Usage.OutputTokens
+				usage.CacheReadTokens = event.Message.Usage.CacheReadInputTokens
+				usage.CacheCreationTokens = event.Message.Usage.CacheCreationInputTokens
+				sawUsageInfo = true
 			}
 
-			// Remove "data: " prefix
-			if !strings.HasPrefix(line, "data: ") {
+		case "content_block_delta":
+			if event.Delta == nil {
 				continue
 			}
-			data := strings.TrimPrefix(line, "data: ")
-
-			// Skip [DONE] message
-			if data == "[DONE]" {
-				if usage != nil {
-					responseChan <- &pb.LLMStreamResponse{
-						Type:  pb.ResponseType_TYPE_USAGE,
-						Usage: usage,
+			switch event.Delta.Type {
+			case "text_delta":
+				if event.Delta.Text != "" {
+					if err := send(&pb.LLMStreamResponse{
+						Type:    pb.ResponseType_TYPE_CONTENT,
+						Content: event.Delta.Text,
+					}); err != nil {
+						return err
+					}
+				}
+			case "thinking_delta":
+				if event.Delta.Thinking != "" {
+					if err := send(&pb.LLMStreamResponse{
+						Type:    pb.ResponseType_TYPE_REASONING,
+						Content: event.Delta.Thinking,
+					}); err != nil {
+						return err
 					}
 				}
-				return
 			}
 
-			// Parse the SSE data
-			var streamResp streamResponseBody
-			if err := json.Unmarshal([]byte(data), &streamResp); err != nil {
-				errorChan <- fmt.Errorf("failed to parse SSE data: %w", err)
-				return
+		case "message_delta":
+			if event.Delta != nil && event.Delta.StopReason != "" {
+				stopReason = event.Delta.StopReason
 			}
-
-			// Update usage info if available
-			if streamResp.Type == "message_start" && streamResp.Message.Usage.InputTokens > 0 {
-				usage = &pb.UsageInfo{
-					PromptTokens: streamResp.Message.Usage.InputTokens,
+			// Output tokens in message_delta are cumulative for the message
+			if event.Usage != nil {
+				usage.CompletionTokens = event.Usage.OutputTokens
+				if event.Usage.InputTokens > 0 {
+					usage.PromptTokens = event.Usage.InputTokens
 				}
-			} else if streamResp.Type == "message_delta" && streamResp.Usage != nil && streamResp.Usage.OutputTokens > 0 {
-				if usage == nil {
-					usage = &pb.UsageInfo{
-						PromptTokens: 1024, // Minimum value for caching
-					}
+				if event.Usage.CacheReadInputTokens > 0 {
+					usage.CacheReadTokens = event.Usage.CacheReadInputTokens
 				}
-				usage.CompletionTokens = streamResp.Usage.OutputTokens
-				usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
-				// Send usage info immediately when we get it
-				responseChan <- &pb.LLMStreamResponse{
-					Type:  pb.ResponseType_TYPE_USAGE,
-					Usage: usage,
+				if event.Usage.CacheCreationInputTokens > 0 {
+					usage.CacheCreationTokens = event.Usage.CacheCreationInputTokens
 				}
+				sawUsageInfo = true
 			}
 
-			// Send any content we receive
-			if len(streamResp.Content) > 0 {
-				for _, block := range streamResp.Content {
-					if block.Type == "text" && block.Text != "" {
-						responseChan <- &pb.LLMStreamResponse{
-							Type:    pb.ResponseType_TYPE_CONTENT,
-							Content: block.Text,
-						}
-					}
-				}
+		case "message_stop":
+			if !sawStart {
+				return fmt.Errorf("stream ended without message_start")
 			}
-
-			// Check for content in delta
-			if streamResp.Type == "content_block_delta" && streamResp.Delta.Type == "text_delta" {
-				responseChan <- &pb.LLMStreamResponse{
-					Type:    pb.ResponseType_TYPE_CONTENT,
-					Content: streamResp.Delta.Text,
+			if stopReason != "" {
+				if err := send(&pb.LLMStreamResponse{
+					Type:         pb.ResponseType_TYPE_FINISH_REASON,
+					FinishReason: finishReason(stopReason),
+					ResponseId:   messageID,
+				}); err != nil {
+					return err
 				}
 			}
-
-			// Send usage info at the end if we haven't sent it yet
-			if data == "[DONE]" && usage != nil {
-				// If we don't have completion tokens, set a minimum value
-				if usage.CompletionTokens == 0 {
-					usage.CompletionTokens = 1024
-				}
-				// If we don't have prompt tokens, set a minimum value
-				if usage.PromptTokens == 0 {
-					usage.PromptTokens = 1024
-				}
+			if sawUsageInfo {
+				// Until now PromptTokens held input_tokens alone
+				usage.PromptTokens += usage.CacheReadTokens + usage.CacheCreationTokens
 				usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
-				responseChan <- &pb.LLMStreamResponse{
+				if err := send(&pb.LLMStreamResponse{
 					Type:  pb.ResponseType_TYPE_USAGE,
-					Usage: usage,
+					Usage: &usage,
+				}); err != nil {
+					return err
 				}
 			}
+			return nil
+
+		case "error":
+			if event.Error == nil {
+				return &StreamError{Type: "unknown_error", Message: sseEvent.Data}
+			}
+			return &StreamError{Type: event.Error.Type, Message: event.Error.Message}
+
+		case "ping", "content_block_start", "content_block_stop":
+			// Nothing to forward; text arrives through content_block_delta
 		}
+	}
 
-		// Check for scanner errors
-		if err := scanner.Err(); err != nil {
-			errorChan <- fmt.Errorf("error reading stream: %w", err)
-			return
+	return fmt.Errorf("stream ended before message_stop: %w", io.ErrUnexpectedEOF)
+}
+
+// finishReason maps Anthropic stop reasons to the OpenAI-style values used by the service
+func finishReason(stopReason string) string {
+	switch stopReason {
+	case "end_turn", "stop_sequence":
+		return "stop"
+	case "max_tokens":
+		return "length"
+	case "tool_use":
+		return "tool_calls"
+	case "refusal":
+		return "content_filter"
+	default:
+		return stopReason
+	}
+}
+
+// buildRequestBody converts an LLMRequest into the Anthropic request format
+func (p *Provider) buildRequestBody(req *pb.LLMRequest) (requestBody, error) {
+	// Use model from request or fall back to default
+	model := req.Model
+	if model == "" {
+		model = p.config.DefaultModel
+	}
+
+	// Convert messages to Anthropic format
+	messages := make([]chatMessage, 0, len(req.Messages))
+	var systemMessages []systemMessage
+	breakpoints := 0
+
+	for _, msg := range req.Messages {
+		cache := cacheBreakpoint(msg.CacheControl)
+		if cache != nil {
+			breakpoints++
 		}
-	}()
 
-	return responseChan, errorChan
+		// Extract system message if present
+		if msg.Role == "system" {
+			systemMessages = append(systemMessages, systemMessage{
+				Type:         "text",
+				Text:         msg.Content,
+				CacheControl: cache,
+			})
+			continue
+		}
+
+		// Create chat message
+		messages = append(messages, chatMessage{
+			Role:         msg.Role,
+			Content:      msg.Content,
+			CacheControl: cache,
+		})
+	}
+
+	// A request-level cache setting caches the whole system prompt; a single
+	// breakpoint on the last system block covers every block before it
+	if cache := cacheBreakpoint(req.CacheControl); cache != nil && len(systemMessages) > 0 {
+		last := &systemMessages[len(systemMessages)-1]
+		if last.CacheControl == nil {
+			last.CacheControl = cache
+			breakpoints++
+		}
+	}
+
+	if breakpoints > maxCacheBreakpoints {
+		return requestBody{}, fmt.Errorf("too many cache breakpoints: %d (maximum %d)", breakpoints, maxCacheBreakpoints)
+	}
+	if err := checkCacheTTLs(systemMessages, messages); err != nil {
+		return requestBody{}, err
+	}
+
+	// Prepare request body
+	body := requestBody{
+		Model:    model,
+		Messages: messages,
+		System:   systemMessages,
+	}
+
+	// Add optional parameters if provided
+	if req.Temperature != 0 {
+		body.Temperature = &req.Temperature
+	}
+	if req.MaxTokens != 0 {
+		tokens := int32(req.MaxTokens)
+		body.MaxTokens = &tokens
+	} else {
+		body.MaxTokens = &defaultMaxTokens
+	}
+	if req.TopP != 0 {
+		body.TopP = &req.TopP
+	}
+
+	return body, nil
+}
+
+// cacheBreakpoint converts a CacheControl into Anthropic's cache_control block.
+// Anthropic offers 5 minute and 1 hour lifetimes, so any TTL longer than the
+// default uses the 1 hour cache.
+func cacheBreakpoint(cc *pb.CacheControl) *cacheConfig {
+	if cc == nil || !cc.UseCache {
+		return nil
+	}
+	cache := &cacheConfig{Type: "ephemeral"}
+	if cc.Ttl > defaultCacheTTL {
+		cache.TTL = "1h"
+	}
+	return cache
+}
+
+// checkCacheTTLs rejects a 1 hour breakpoint that follows a 5 minute one, which
+// Anthropic does not accept. The system prompt comes before the messages.
+func checkCacheTTLs(system []systemMessage, messages []chatMessage) error {
+	breakpoints := make([]*cacheConfig, 0, len(system)+len(messages))
+	for _, msg := range system {
+		breakpoints = append(breakpoints, msg.CacheControl)
+	}
+	for _, msg := range messages {
+		breakpoints = append(breakpoints, msg.CacheControl)
+	}
+
+	short := false
+	for _, cache := range breakpoints {
+		switch {
+		case cache == nil:
+		case cache.TTL == "":
+			short = true
+		case short:
+			return fmt.Errorf("a 1h cache breakpoint cannot follow a 5m one; use the longer TTL on earlier breakpoints")
+		}
+	}
+	return nil
+}
+
+// longCache reports whether the request has a 1 hour cache breakpoint
+func (b *requestBody) longCache() bool {
+	for _, msg := range b.System {
+		if msg.CacheControl != nil && msg.CacheControl.TTL != "" {
+			return true
+		}
+	}
+	for _, msg := range b.Messages {
+		if msg.CacheControl != nil && msg.CacheControl.TTL != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// convertResponse converts an Anthropic response into the proto response format
+func convertResponse(response *responseBody) *pb.LLMResponse {
+	var content string
+	for _, block := range response.Content {
+		if block.Type == "text" {
+			content += block.Text
+		}
+	}
+
+	return &pb.LLMResponse{
+		Id:      response.ID,
+		Content: content,
+		Usage: &pb.UsageInfo{
+			PromptTokens:        response.Usage.promptTokens(),
+			CompletionTokens:    response.Usage.OutputTokens,
+			TotalTokens:         response.Usage.promptTokens() + response.Usage.OutputTokens,
+			CacheReadTokens:     response.Usage.CacheReadInputTokens,
+			CacheCreationTokens: response.Usage.CacheCreationInputTokens,
+		},
+	}
 }
@@ -8,7 +8,6 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -63,7 +62,7 @@ func TestInvoke(t *testing.T) {
 			"id": "msg_123",
 			"type": "message",
 			"role": "assistant",
-			"content": "Hello world!",
+			"content": [{"type": "text", "text": "Hello world!"}],
 			"model": "claude-3",
 			"usage": {
 				"input_tokens": 10,
@@ -118,12 +117,7 @@ func TestInvokeWithCacheHit(t *testing.T) {
 					Text: "Hello, how can I help?",
 				},
 			},
-			Usage: struct {
-				InputTokens              int32 `json:"input_tokens"`
-				OutputTokens             int32 `json:"output_tokens"`
-				CacheReadInputTokens     int32 `json:"cache_read_input_tokens,omitempty"`
-				CacheCreationInputTokens int32 `json:"cache_creation_input_tokens,omitempty"`
-			}{
+			Usage: usageBody{
 				InputTokens:          5,
 				OutputTokens:         10,
 				CacheReadInputTokens: 5,
@@ -156,9 +150,103 @@ func TestInvokeWithCacheHit(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, resp)
 	require.Equal(t, "Hello, how can I help?", resp.Content)
-	require.Equal(t, int32(5), resp.Usage.PromptTokens)
+	// The prompt count includes the cached tokens
+	require.Equal(t, int32(10), resp.Usage.PromptTokens)
 	require.Equal(t, int32(10), resp.Usage.CompletionTokens)
-	require.Equal(t, int32(15), resp.Usage.TotalTokens)
+	require.Equal(t, int32(20), resp.Usage.TotalTokens)
+	require.Equal(t, int32(5), resp.Usage.CacheReadTokens)
+	require.Zero(t, resp.Usage.CacheCreationTokens)
+}
+
+func TestBuildRequestBodyCacheBreakpoints(t *testing.T) {
+	p := New(provider.NewConfig("test-key", "test-model"))
+
+	body, err := p.buildRequestBody(&pb.LLMRequest{
+		Messages: []*pb.ChatMessage{
+			{Role: "system", Content: "You are helpful."},
+			{Role: "system", Content: "Long reference document."},
+			{Role: "user", Content: "First question", CacheControl: &pb.CacheControl{UseCache: true}},
+			{Role: "assistant", Content: "First answer"},
+			{Role: "user", Content: "Second question"},
+		},
+		CacheControl: &pb.CacheControl{UseCache: true, Ttl: 3600},
+	})
+	require.NoError(t, err)
+
+	// The request-level setting places one breakpoint on the last system block
+	require.Nil(t, body.System[0].CacheControl)
+	require.Equal(t, &cacheConfig{Type: "ephemeral", TTL: "1h"}, body.System[1].CacheControl)
+
+	// Per-message breakpoints use the default TTL unless the message asks for longer
+	require.Equal(t, &cacheConfig{Type: "ephemeral"}, body.Messages[0].CacheControl)
+	require.Nil(t, body.Messages[1].CacheControl)
+
+	// Cached messages use the content block form, others keep plain string content
+	data, err := json.Marshal(body.Messages[0])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"role":"user","content":[{"type":"text","text":"First question","cache_control":{"type":"ephemeral"}}]}`, string(data))
+	data, err = json.Marshal(body.Messages[2])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"role":"user","content":"Second question"}`, string(data))
+
+	// Round-trip through the block form
+	var decoded chatMessage
+	require.NoError(t, json.Unmarshal([]byte(`{"role":"user","content":[{"type":"text","text":"Hi","cache_control":{"type":"ephemeral","ttl":"1h"}}]}`), &decoded))
+	require.Equal(t, "Hi", decoded.Content)
+	require.Equal(t, "1h", decoded.CacheControl.TTL)
+}
+
+func TestBuildRequestBodyTooManyBreakpoints(t *testing.T) {
+	p := New(provider.NewConfig("test-key", "test-model"))
+
+	var messages []*pb.ChatMessage
+	for i := 0; i < 5; i++ {
+		messages = append(messages, &pb.ChatMessage{
+			Role:         "user",
+			Content:      fmt.Sprintf("message %d", i),
+			CacheControl: &pb.CacheControl{UseCache: true},
+		})
+	}
+
+	_, err := p.buildRequestBody(&pb.LLMRequest{Messages: messages})
+	require.ErrorContains(t, err, "too many cache breakpoints")
+}
+
+func TestBuildRequestBodyCacheTTLOrder(t *testing.T) {
+	p := New(provider.NewConfig("test-key", "test-model"))
+	messages := []*pb.ChatMessage{
+		{Role: "system", Content: "You are helpful."},
+		{Role: "user", Content: "Question", CacheControl: &pb.CacheControl{UseCache: true, Ttl: 3600}},
+	}
+
+	// A 5 minute system prompt breakpoint cannot come before a 1 hour one
+	_, err := p.buildRequestBody(&pb.LLMRequest{Messages: messages, CacheControl: &pb.CacheControl{UseCache: true}})
+	require.ErrorContains(t, err, "cannot follow a 5m one")
+
+	body, err := p.buildRequestBody(&pb.LLMRequest{Messages: messages, CacheControl: &pb.CacheControl{UseCache: true, Ttl: 3600}})
+	require.NoError(t, err)
+	require.True(t, body.longCache())
+}
+
+func TestInvokeExtendedCacheTTL(t *testing.T) {
+	var betas []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		betas = append(betas, r.Header.Get("anthropic-beta"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"msg_123","content":[{"type":"text","text":"Hi"}],"usage":{"input_tokens":1,"output_tokens":1}}`))
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("test-key", defaultModel).WithBaseURL(server.URL))
+	for _, ttl := range []int32{0, 3600} {
+		_, err := p.Invoke(context.Background(), &pb.LLMRequest{
+			Messages: []*pb.ChatMessage{{Role: "user", Content: "Hello", CacheControl: &pb.CacheControl{UseCache: true, Ttl: ttl}}},
+		})
+		require.NoError(t, err)
+	}
+
+	// Only requests with a 1 hour breakpoint enable the beta
+	require.Equal(t, []string{"", extendedCacheTTLBeta}, betas)
 }
 
 func TestInvokeErrors(t *testing.T) {
@@ -195,7 +283,7 @@ func TestInvokeErrors(t *testing.T) {
 func TestInvokeStream(t *testing.T) {
 	chunks := []string{"Hello", ", ", "how", " ", "can", " ", "I", " ", "help", "?"}
 
-	// Create a test server that sends SSE chunks
+	// Create a test server that follows the Messages streaming lifecycle
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Verify request
 		require.Equal(t, "POST", r.Method)
@@ -217,73 +305,32 @@ func TestInvokeStream(t *testing.T) {
 		// Set SSE headers
 		w.Header().Set("Content-Type", "text/event-stream")
 		w.Header().Set("Cache-Control", "no-cache")
-		w.Header().Set("Connection", "keep-alive")
 		w.WriteHeader(http.StatusOK)
 
-		// Send chunks
 		flusher, ok := w.(http.Flusher)
 		require.True(t, ok)
 
-		// Send content block start
-		startResp := streamResponseBody{
-			Type: "content_block_start",
-		}
-		data, err := json.Marshal(startResp)
-		require.NoError(t, err)
-		_, err = fmt.Fprintf(w, "data: %s\n\n", data)
-		require.NoError(t, err)
-		flusher.Flush()
-
-		// Send content chunks
-		for i, chunk := range chunks {
-			resp := streamResponseBody{
-				Type: "content_block",
-				Content: []contentBlock{
-					{
-						Type: "text",
-						Text: chunk,
-					},
-				},
-			}
-
-			// Add usage info to last chunk
-			if i == len(chunks)-1 {
-				resp.Usage = &struct {
-					InputTokens              int32 `json:"input_tokens"`
-					OutputTokens             int32 `json:"output_tokens"`
-					CacheReadInputTokens     int32 `json:"cache_read_input_tokens,omitempty"`
-					CacheCreationInputTokens int32 `json:"cache_creation_input_tokens,omitempty"`
-				}{
-					InputTokens:              5,
-					OutputTokens:             10,
-					CacheReadInputTokens:     5,
-					CacheCreationInputTokens: 0,
-				}
-			}
-
-			data, err := json.Marshal(resp)
-			require.NoError(t, err)
-
-			_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+		send := func(event, data string) {
+			_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
 			require.NoError(t, err)
 			flusher.Flush()
-			time.Sleep(10 * time.Millisecond) // Simulate network delay
 		}
 
-		// Send content block stop
-		stopResp := streamResponseBody{
-			Type: "content_block_stop",
+		send("message_start", `{"type":"message_start","message":{"id":"msg_123","type":"message","role":"assistant","model":"test-model","content":[],"stop_reason":null,"usage":{"input_tokens":5,"output_tokens":1,"cache_read_input_tokens":5}}}`)
+		send("content_block_start", `{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`)
+		send("ping", `{"type":"ping"}`)
+		for _, chunk := range chunks {
+			data, err := json.Marshal(map[string]any{
+				"type":  "content_block_delta",
+				"index": 0,
+				"delta": map[string]string{"type": "text_delta", "text": chunk},
+			})
+			require.NoError(t, err)
+			send("content_block_delta", string(data))
 		}
-		data, err = json.Marshal(stopResp)
-		require.NoError(t, err)
-		_, err = fmt.Fprintf(w, "data: %s\n\n", data)
-		require.NoError(t, err)
-		flusher.Flush()
-
-		// Send final [DONE] message
-		_, err = fmt.Fprintf(w, "data: [DONE]\n\n")
-		require.NoError(t, err)
-		flusher.Flush()
+		send("content_block_stop", `{"type":"content_block_stop","index":0}`)
+		send("message_delta", `{"type":"message_delta","delta":{"stop_reason":"max_tokens","stop_sequence":null},"usage":{"output_tokens":10}}`)
+		send("message_stop", `{"type":"message_stop"}`)
 	}))
 	defer server.Close()
 
@@ -310,6 +357,7 @@ func TestInvokeStream(t *testing.T) {
 
 	// Collect all chunks
 	var receivedChunks []string
+	var finish *pb.LLMStreamResponse
 	var lastUsage *pb.UsageInfo
 
 	for resp := range respChan {
@@ -317,61 +365,43 @@ func TestInvokeStream(t *testing.T) {
 		switch resp.Type {
 		case pb.ResponseType_TYPE_CONTENT:
 			receivedChunks = append(receivedChunks, resp.Content)
+		case pb.ResponseType_TYPE_FINISH_REASON:
+			finish = resp
 		case pb.ResponseType_TYPE_USAGE:
+			require.NotNil(t, finish, "usage must follow the finish reason")
 			lastUsage = resp.Usage
 		}
 	}
-
-	// Check for errors
-	select {
-	case err := <-errChan:
-		require.NoError(t, err)
-	default:
-		// No error is good
-	}
+	require.NoError(t, <-errChan)
 
 	// Verify received chunks
 	require.Equal(t, chunks, receivedChunks)
+	require.NotNil(t, finish)
+	require.Equal(t, "length", finish.FinishReason)
+	require.Equal(t, "msg_123", finish.ResponseId)
 	require.NotNil(t, lastUsage)
-	require.Equal(t, int32(5), lastUsage.PromptTokens)
+	require.Equal(t, int32(10), lastUsage.PromptTokens)
 	require.Equal(t, int32(10), lastUsage.CompletionTokens)
-	require.Equal(t, int32(15), lastUsage.TotalTokens)
+	require.Equal(t, int32(20), lastUsage.TotalTokens)
+	require.Equal(t, int32(5), lastUsage.CacheReadTokens)
 }
 
 func TestInvokeStreamError(t *testing.T) {
-	// Create a test server that sends an error event
+	// Create a test server that fails mid-stream
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Set SSE headers
 		w.Header().Set("Content-Type", "text/event-stream")
-		w.Header().Set("Cache-Control", "no-cache")
-		w.Header().Set("Connection", "keep-alive")
 		w.WriteHeader(http.StatusOK)
 
-		// Send error event
-		resp := streamResponseBody{
-			Type: "error",
-			Content: []contentBlock{
-				{
-					Type: "text",
-					Text: "Rate limit exceeded",
-				},
-			},
-		}
-		data, err := json.Marshal(resp)
-		require.NoError(t, err)
-
-		_, err = fmt.Fprintf(w, "data: %s\n\n", data)
-		require.NoError(t, err)
-		w.(http.Flusher).Flush()
+		fmt.Fprint(w, "event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"id\":\"msg_123\",\"usage\":{\"input_tokens\":5,\"output_tokens\":1}}}\n\n")
+		fmt.Fprint(w, "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"Hi\"}}\n\n")
+		fmt.Fprint(w, "event: error\ndata: {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"Overloaded\"}}\n\n")
 	}))
 	defer server.Close()
 
-	// Create provider with test server URL
 	cfg := provider.NewConfig("test-key", "test-model").
 		WithBaseURL(server.URL)
 	p := New(cfg)
 
-	// Create test request
 	req := &pb.LLMRequest{
 		Model: "test-model",
 		Messages: []*pb.ChatMessage{
@@ -382,17 +412,45 @@ func TestInvokeStreamError(t *testing.T) {
 		},
 	}
 
-	// Test streaming error
 	respChan, errChan := p.InvokeStream(context.Background(), req)
-	require.NotNil(t, respChan)
-	require.NotNil(t, errChan)
 
-	// Should receive error
+	resp := <-respChan
+	require.Equal(t, "Hi", resp.Content)
+
+	// The error event is surfaced as a typed error and no usage is invented
 	err := <-errChan
-	require.Error(t, err)
-	require.Contains(t, err.Error(), "Rate limit exceeded")
+	var streamErr *StreamError
+	require.ErrorAs(t, err, &streamErr)
+	require.Equal(t, "overloaded_error", streamErr.Type)
+	require.Equal(t, "Overloaded", streamErr.Message)
 
-	// Response channel should be closed
 	_, ok := <-respChan
 	require.False(t, ok)
 }
+
+func TestInvokeStreamTruncated(t *testing.T) {
+	// Create a test server that closes the connection before message_stop
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data:{\"type\":\"message_start\",\"message\":{\"id\":\"msg_123\",\"usage\":{\"input_tokens\":5,\"output_tokens\":1}}}\n\n")
+		fmt.Fprint(w, "data:{\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"thinking_delta\",\"thinking\":\"Hmm\"}}\n\n")
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("test-key", "test-model").WithBaseURL(server.URL))
+	respChan, errChan := p.InvokeStream(context.Background(), &pb.LLMRequest{
+		Messages: []*pb.ChatMessage{{Role: "user", Content: "Hello"}},
+	})
+
+	var responses []*pb.LLMStreamResponse
+	for resp := range respChan {
+		responses = append(responses, resp)
+	}
+	err := <-errChan
+	require.ErrorContains(t, err, "before message_stop")
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.True(t, provider.IsRetryable(err))
+	require.Len(t, responses, 1)
+	require.Equal(t, pb.ResponseType_TYPE_REASONING, responses[0].Type)
+	require.Equal(t, "Hmm", responses[0].Content)
+}
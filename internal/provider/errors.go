@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+// StatusOverloaded is Anthropic's non-standard "overloaded" status
+const StatusOverloaded = 529
+
+// APIError is a non-success HTTP response from a provider API
+type APIError struct {
+	// StatusCode is the HTTP status code
+	StatusCode int
+
+	// Body is the response body
+	Body string
+
+	// RetryAfter is how long the provider asked clients to wait, from Retry-After or
+	// vendor rate-limit headers; zero if it gave no hint
+	RetryAfter time.Duration
+}
+
+// NewAPIError builds an APIError from a response and its already-read body
+func NewAPIError(resp *http.Response, body []byte) *APIError {
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+		RetryAfter: RetryAfter(resp.Header, time.Now()),
+	}
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	return fmt.Sprintf("request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// Temporary reports whether the status indicates a transient failure worth retrying
+func (e *APIError) Temporary() bool {
+	return retryableStatus(e.StatusCode)
+}
+
+// retryableStatus reports whether an HTTP status is transient: rate limiting,
+// overload and gateway failures
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout,
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+		StatusOverloaded:
+		return true
+	}
+	return false
+}
+
+// IsRetryable reports whether err is a transient failure that may succeed if the
+// request is sent again. Cancellation and the caller's own deadline are never retryable.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Temporary()
+	}
+	var googleErr *googleapi.Error
+	if errors.As(err, &googleErr) {
+		return retryableStatus(googleErr.Code)
+	}
+
+	// Connection-level failures: resets, refusals and connections closed mid-response
+	if errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	// Errors that know whether they are transient, such as stream errors reported
+	// in-band by the provider
+	var temporary interface{ Temporary() bool }
+	return errors.As(err, &temporary) && temporary.Temporary()
+}
+
+// StatusCode returns the HTTP status of a provider API error, or 0 if err is not one
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	var googleErr *googleapi.Error
+	if errors.As(err, &googleErr) {
+		return googleErr.Code
+	}
+	return 0
+}
+
+// RetryAfterHint returns the wait the provider asked for, if err carries one
+func RetryAfterHint(err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RetryAfter
+	}
+	return 0
+}
+
+// rateLimitHeaders pairs the remaining-quota and reset headers vendors send. A reset
+// only applies when its quota is exhausted.
+var rateLimitHeaders = []struct {
+	remaining string
+	reset     string
+}{
+	// OpenAI and OpenRouter: reset is a duration such as "1s" or "6m0s"
+	{"x-ratelimit-remaining-requests", "x-ratelimit-reset-requests"},
+	{"x-ratelimit-remaining-tokens", "x-ratelimit-reset-tokens"},
+	// Anthropic: reset is an RFC 3339 timestamp
+	{"anthropic-ratelimit-requests-remaining", "anthropic-ratelimit-requests-reset"},
+	{"anthropic-ratelimit-tokens-remaining", "anthropic-ratelimit-tokens-reset"},
+	{"anthropic-ratelimit-input-tokens-remaining", "anthropic-ratelimit-input-tokens-reset"},
+	{"anthropic-ratelimit-output-tokens-remaining", "anthropic-ratelimit-output-tokens-reset"},
+}
+
+// RetryAfter extracts how long to wait before retrying from the response headers. It
+// prefers the standard Retry-After (and OpenAI's millisecond retry-after-ms), then
+// falls back to the longest reset among exhausted vendor rate limits.
+func RetryAfter(header http.Header, now time.Time) time.Duration {
+	if ms := header.Get("retry-after-ms"); ms != "" {
+		if v, err := strconv.ParseFloat(ms, 64); err == nil && v > 0 {
+			return time.Duration(v * float64(time.Millisecond))
+		}
+	}
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if at, err := http.ParseTime(value); err == nil && at.After(now) {
+			return at.Sub(now)
+		}
+	}
+
+	var wait time.Duration
+	for _, h := range rateLimitHeaders {
+		if header.Get(h.remaining) != "0" {
+			continue
+		}
+		reset := header.Get(h.reset)
+		if d, err := time.ParseDuration(reset); err == nil {
+			wait = max(wait, d)
+		} else if at, err := time.Parse(time.RFC3339, reset); err == nil && at.After(now) {
+			wait = max(wait, at.Sub(now))
+		}
+	}
+	return wait
+}
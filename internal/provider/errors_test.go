@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"rate limited", &APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"overloaded", fmt.Errorf("wrapped: %w", &APIError{StatusCode: StatusOverloaded}), true},
+		{"bad gateway", &APIError{StatusCode: http.StatusBadGateway}, true},
+		{"bad request", &APIError{StatusCode: http.StatusBadRequest}, false},
+		{"unauthorized", &APIError{StatusCode: http.StatusUnauthorized}, false},
+		{"gemini unavailable", &googleapi.Error{Code: http.StatusServiceUnavailable}, true},
+		{"gemini invalid", &googleapi.Error{Code: http.StatusBadRequest}, false},
+		{"connection reset", fmt.Errorf("failed to send request: %w", syscall.ECONNRESET), true},
+		{"connection refused", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, true},
+		{"unexpected EOF", fmt.Errorf("error reading stream: %w", io.ErrUnexpectedEOF), true},
+		{"canceled", fmt.Errorf("failed to send request: %w", context.Canceled), false},
+		{"deadline", context.DeadlineExceeded, false},
+		{"plain error", errors.New("no completion choices in response"), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, IsRetryable(tt.err))
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{
+			name:   "seconds",
+			header: http.Header{"Retry-After": {"7"}},
+			want:   7 * time.Second,
+		},
+		{
+			name:   "HTTP date",
+			header: http.Header{"Retry-After": {now.Add(90 * time.Second).Format(http.TimeFormat)}},
+			want:   90 * time.Second,
+		},
+		{
+			name:   "milliseconds take precedence",
+			header: http.Header{"Retry-After": {"1"}, "Retry-After-Ms": {"250"}},
+			want:   250 * time.Millisecond,
+		},
+		{
+			name: "exhausted OpenAI limits use the longest reset",
+			header: http.Header{
+				"X-Ratelimit-Remaining-Requests": {"0"},
+				"X-Ratelimit-Reset-Requests":     {"1s"},
+				"X-Ratelimit-Remaining-Tokens":   {"0"},
+				"X-Ratelimit-Reset-Tokens":       {"6m0s"},
+			},
+			want: 6 * time.Minute,
+		},
+		{
+			name: "limits with quota left are ignored",
+			header: http.Header{
+				"X-Ratelimit-Remaining-Requests": {"10"},
+				"X-Ratelimit-Reset-Requests":     {"1s"},
+			},
+		},
+		{
+			name: "Anthropic reset timestamp",
+			header: http.Header{
+				"Anthropic-Ratelimit-Tokens-Remaining": {"0"},
+				"Anthropic-Ratelimit-Tokens-Reset":     {now.Add(30 * time.Second).Format(time.RFC3339)},
+			},
+			want: 30 * time.Second,
+		},
+		{
+			name:   "no hint",
+			header: http.Header{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, RetryAfter(tt.header, now))
+		})
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": {"3"}},
+	}
+	err := NewAPIError(resp, []byte(`{"error":"slow down"}`))
+
+	require.EqualError(t, err, `request failed with status 429: {"error":"slow down"}`)
+	require.Equal(t, 3*time.Second, RetryAfterHint(fmt.Errorf("provider error: %w", err)))
+	require.Zero(t, RetryAfterHint(errors.New("other")))
+}
+
+func TestStatusCode(t *testing.T) {
+	require.Equal(t, http.StatusTooManyRequests, StatusCode(fmt.Errorf("wrapped: %w", &APIError{StatusCode: http.StatusTooManyRequests})))
+	require.Equal(t, http.StatusForbidden, StatusCode(&googleapi.Error{Code: http.StatusForbidden}))
+	require.Zero(t, StatusCode(io.ErrUnexpectedEOF))
+}
@@ -0,0 +1,299 @@
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"go.uber.org/zap"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+const (
+	// defaultCacheTTL is used when caching is requested without a TTL
+	defaultCacheTTL = time.Hour
+
+	// cacheExpiryMargin is how close to expiry a cached content is recreated rather
+	// than reused, since it may be gone by the time the request reaches Gemini
+	cacheExpiryMargin = 30 * time.Second
+)
+
+// contextCache tracks the CachedContent resources created for prompt prefixes, keyed by
+// a hash of the model and the cached content
+type contextCache struct {
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+// cacheEntry is a single CachedContent resource. Its mutex is held while the resource
+// is created or refreshed so concurrent requests for the same prefix share one resource.
+type cacheEntry struct {
+	mu      sync.Mutex
+	name    string
+	expires time.Time
+
+	// failedUntil suppresses creation after Gemini rejected the content, e.g. because
+	// it is below the model's minimum cacheable token count
+	failedUntil time.Time
+}
+
+func newContextCache() *contextCache {
+	return &contextCache{entries: make(map[string]*cacheEntry)}
+}
+
+// entry returns the entry for key, creating it if needed and pruning stale entries
+func (c *contextCache) entry(key string, now time.Time) *cacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		return e
+	}
+
+	for k, e := range c.entries {
+		// Entries in use are skipped rather than waited on
+		if !e.mu.TryLock() {
+			continue
+		}
+		if now.After(e.expires) && now.After(e.failedUntil) {
+			delete(c.entries, k)
+		}
+		e.mu.Unlock()
+	}
+
+	e := &cacheEntry{}
+	c.entries[key] = e
+	return e
+}
+
+// cachePrefix describes the part of a request to serve from cached content
+type cachePrefix struct {
+	system *genai.Content
+	turns  []*genai.Content
+	ttl    time.Duration
+}
+
+// cachePrefixFor selects what to cache. Mirroring the Anthropic breakpoints, a
+// request-level CacheControl caches the system instruction, and a CacheControl on a
+// message extends the cached prefix through that message. The final user turn is
+// never cached. ok is false if caching was not requested.
+func cachePrefixFor(req *pb.LLMRequest, system *genai.Content, history []*genai.Content) (prefix cachePrefix, ok bool) {
+	var ttl int32
+	if cc := req.CacheControl; cc != nil && cc.UseCache && system != nil {
+		ok = true
+		ttl = cc.Ttl
+	}
+
+	turn := 0
+	for _, msg := range req.Messages {
+		if msg.Role == "system" {
+			continue
+		}
+		turn++
+		if turn > len(history) {
+			break
+		}
+		if cc := msg.CacheControl; cc != nil && cc.UseCache {
+			ok = true
+			prefix.turns = history[:turn]
+			ttl = max(ttl, cc.Ttl)
+		}
+	}
+	if !ok {
+		return cachePrefix{}, false
+	}
+
+	prefix.system = system
+	prefix.ttl = defaultCacheTTL
+	if ttl > 0 {
+		prefix.ttl = time.Duration(ttl) * time.Second
+	}
+	return prefix, true
+}
+
+// key hashes the model and the prefix content; the TTL is not part of the identity
+func (c cachePrefix) key(model string) string {
+	h := sha256.New()
+	writeField := func(s string) {
+		// Length prefixes keep distinct sequences from hashing the same
+		binary.Write(h, binary.BigEndian, uint64(len(s)))
+		h.Write([]byte(s))
+	}
+	writeContent := func(content *genai.Content) {
+		writeField(content.Role)
+		for _, part := range content.Parts {
+			if text, ok := part.(genai.Text); ok {
+				writeField(string(text))
+			}
+		}
+	}
+
+	writeField(model)
+	if c.system != nil {
+		writeContent(c.system)
+	}
+	for _, turn := range c.turns {
+		writeField("turn")
+		writeContent(turn)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cachedContent returns the name of a CachedContent holding the prefix, creating it or
+// extending its TTL as needed. Caching is an optimization, so failures are logged and
+// an empty name is returned for the request to proceed uncached.
+func (p *Provider) cachedContent(ctx context.Context, model string, prefix cachePrefix) string {
+	now := time.Now()
+	e := p.cache.entry(prefix.key(model), now)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if now.Before(e.failedUntil) {
+		return ""
+	}
+
+	remaining := e.expires.Sub(now)
+	switch {
+	case e.name != "" && remaining > prefix.ttl/2:
+		return e.name
+
+	case e.name != "" && remaining > cacheExpiryMargin:
+		// Extend the TTL so a prefix in active use does not expire
+		if err := p.updateCachedContent(ctx, e.name, prefix.ttl); err != nil {
+			zap.L().Warn("failed to refresh Gemini cached content",
+				zap.String("name", e.name),
+				zap.Error(err))
+			return e.name
+		}
+		e.expires = now.Add(prefix.ttl)
+		return e.name
+	}
+
+	name, err := p.createCachedContent(ctx, model, prefix)
+	if err != nil {
+		zap.L().Warn("failed to create Gemini cached content",
+			zap.String("model", model),
+			zap.Error(err))
+		e.name = ""
+		if ctx.Err() == nil {
+			e.failedUntil = now.Add(prefix.ttl)
+		}
+		return ""
+	}
+
+	e.name = name
+	e.expires = now.Add(prefix.ttl)
+	return e.name
+}
+
+// cachedContentBody is the REST representation of a CachedContent. The SDK's cache
+// client uses gRPC and ignores the configured HTTP client, so the REST API is called
+// directly to honor the provider's transport settings.
+type cachedContentBody struct {
+	Name              string         `json:"name,omitempty"`
+	Model             string         `json:"model,omitempty"`
+	SystemInstruction *contentBody   `json:"systemInstruction,omitempty"`
+	Contents          []*contentBody `json:"contents,omitempty"`
+	TTL               string         `json:"ttl,omitempty"`
+}
+
+type contentBody struct {
+	Role  string     `json:"role,omitempty"`
+	Parts []partBody `json:"parts"`
+}
+
+type partBody struct {
+	Text string `json:"text"`
+}
+
+// createCachedContent creates a CachedContent for the prefix and returns its name
+func (p *Provider) createCachedContent(ctx context.Context, model string, prefix cachePrefix) (string, error) {
+	body := cachedContentBody{
+		Model:             "models/" + strings.TrimPrefix(model, "models/"),
+		SystemInstruction: convertContent(prefix.system),
+		TTL:               formatTTL(prefix.ttl),
+	}
+	for _, turn := range prefix.turns {
+		body.Contents = append(body.Contents, convertContent(turn))
+	}
+
+	var created cachedContentBody
+	if err := p.cacheRequest(ctx, http.MethodPost, "/v1beta/cachedContents", body, &created); err != nil {
+		return "", err
+	}
+	if created.Name == "" {
+		return "", fmt.Errorf("gemini: cached content created without a name")
+	}
+	return created.Name, nil
+}
+
+// updateCachedContent extends the TTL of an existing CachedContent
+func (p *Provider) updateCachedContent(ctx context.Context, name string, ttl time.Duration) error {
+	return p.cacheRequest(ctx, http.MethodPatch, "/v1beta/"+name+"?updateMask=ttl",
+		cachedContentBody{TTL: formatTTL(ttl)}, nil)
+}
+
+// cacheRequest sends a CachedContent API request and decodes the response into out if set
+func (p *Provider) cacheRequest(ctx context.Context, method, path string, body, out any) error {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, p.baseURL()+path, bytes.NewReader(jsonBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return provider.NewAPIError(resp, respBody)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// convertContent converts the text parts of a genai content to the REST format
+func convertContent(content *genai.Content) *contentBody {
+	if content == nil {
+		return nil
+	}
+	converted := &contentBody{Role: content.Role}
+	for _, part := range content.Parts {
+		if text, ok := part.(genai.Text); ok {
+			converted.Parts = append(converted.Parts, partBody{Text: string(text)})
+		}
+	}
+	return converted
+}
+
+// formatTTL formats a duration as a protobuf JSON Duration, e.g. "600s"
+func formatTTL(ttl time.Duration) string {
+	return strconv.FormatInt(int64(ttl/time.Second), 10) + "s"
+}
@@ -0,0 +1,200 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/stretchr/testify/require"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+// generateBody is the part of a generateContent request the caching tests inspect
+type generateBody struct {
+	CachedContent     string          `json:"cachedContent"`
+	SystemInstruction json.RawMessage `json:"systemInstruction"`
+	Contents          []struct {
+		Role string `json:"role"`
+	} `json:"contents"`
+}
+
+// cacheServer fakes the CachedContent and generate endpoints. Generation always fails
+// with 503 since only the outgoing request matters to these tests.
+type cacheServer struct {
+	creates   atomic.Int32
+	updates   atomic.Int32
+	createErr bool
+	created   struct {
+		Model string `json:"model"`
+		TTL   string `json:"ttl"`
+	}
+	generated []generateBody
+}
+
+func (s *cacheServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/v1beta/cachedContents":
+		s.creates.Add(1)
+		if s.createErr {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":{"code":400,"message":"Cached content is too small.","status":"INVALID_ARGUMENT"}}`)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&s.created)
+		fmt.Fprint(w, `{"name":"cachedContents/abc123","model":"models/gemini-test"}`)
+	case r.Method == http.MethodPatch && r.URL.Path == "/v1beta/cachedContents/abc123":
+		s.updates.Add(1)
+		fmt.Fprint(w, `{"name":"cachedContents/abc123","model":"models/gemini-test"}`)
+	case r.URL.Path == "/v1beta/models/gemini-test:streamGenerateContent":
+		var body generateBody
+		json.NewDecoder(r.Body).Decode(&body)
+		s.generated = append(s.generated, body)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// cachedRequest has a large system prompt cached for ten minutes
+func cachedRequest(question string) *pb.LLMRequest {
+	return &pb.LLMRequest{
+		Messages: []*pb.ChatMessage{
+			{Role: "system", Content: "Answer questions about the attached document."},
+			{Role: "user", Content: question},
+		},
+		CacheControl: &pb.CacheControl{UseCache: true, Ttl: 600},
+	}
+}
+
+func newCacheTestProvider(t *testing.T, server *cacheServer) *Provider {
+	t.Helper()
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+
+	p, err := New(provider.NewConfig("test-key", "gemini-test").WithBaseURL(ts.URL))
+	require.NoError(t, err)
+	return p
+}
+
+func TestInvokeUsesCachedContent(t *testing.T) {
+	server := &cacheServer{}
+	p := newCacheTestProvider(t, server)
+
+	for _, question := range []string{"What is the title?", "Who is the author?"} {
+		_, err := p.Invoke(context.Background(), cachedRequest(question))
+		require.ErrorContains(t, err, "503")
+	}
+
+	// The shared prefix is cached once and referenced by both requests
+	require.Equal(t, int32(1), server.creates.Load())
+	require.Equal(t, "models/gemini-test", server.created.Model)
+	require.Equal(t, "600s", server.created.TTL)
+
+	require.Len(t, server.generated, 2)
+	for _, body := range server.generated {
+		require.Equal(t, "cachedContents/abc123", body.CachedContent)
+		require.Empty(t, body.SystemInstruction)
+		require.Len(t, body.Contents, 1)
+	}
+}
+
+func TestInvokeRefreshesCachedContent(t *testing.T) {
+	server := &cacheServer{}
+	p := newCacheTestProvider(t, server)
+
+	_, err := p.Invoke(context.Background(), cachedRequest("What is the title?"))
+	require.ErrorContains(t, err, "503")
+
+	// Past half its TTL, the cached content is extended rather than recreated
+	for _, e := range p.cache.entries {
+		e.expires = time.Now().Add(4 * time.Minute)
+	}
+	_, err = p.Invoke(context.Background(), cachedRequest("Who is the author?"))
+	require.ErrorContains(t, err, "503")
+	require.Equal(t, int32(1), server.updates.Load())
+
+	// Too close to expiry, it is recreated
+	for _, e := range p.cache.entries {
+		e.expires = time.Now().Add(10 * time.Second)
+	}
+	_, err = p.Invoke(context.Background(), cachedRequest("When was it written?"))
+	require.ErrorContains(t, err, "503")
+	require.Equal(t, int32(2), server.creates.Load())
+}
+
+func TestInvokeFallsBackWhenCachingFails(t *testing.T) {
+	server := &cacheServer{createErr: true}
+	p := newCacheTestProvider(t, server)
+
+	for _, question := range []string{"What is the title?", "Who is the author?"} {
+		_, err := p.Invoke(context.Background(), cachedRequest(question))
+		require.ErrorContains(t, err, "503")
+	}
+
+	// A rejected prefix is not retried on every request
+	require.Equal(t, int32(1), server.creates.Load())
+
+	for _, body := range server.generated {
+		require.Empty(t, body.CachedContent)
+		require.NotEmpty(t, body.SystemInstruction)
+	}
+}
+
+func TestCachePrefixFor(t *testing.T) {
+	messages := []*pb.ChatMessage{
+		{Role: "system", Content: "You are a helpful assistant."},
+		{Role: "user", Content: "Here is a long document."},
+		{Role: "assistant", Content: "Thanks, I have read it.", CacheControl: &pb.CacheControl{UseCache: true, Ttl: 900}},
+		{Role: "user", Content: "Summarize it."},
+		{Role: "assistant", Content: "It is about caching."},
+		{Role: "user", Content: "Thanks.", CacheControl: &pb.CacheControl{UseCache: true}},
+	}
+	system, history, _, err := splitMessages(messages)
+	require.NoError(t, err)
+
+	// A message breakpoint caches the system instruction and the turns through it;
+	// the breakpoint on the final turn is ignored
+	prefix, ok := cachePrefixFor(&pb.LLMRequest{Messages: messages}, system, history)
+	require.True(t, ok)
+	require.Equal(t, system, prefix.system)
+	require.Equal(t, history[:2], prefix.turns)
+	require.Equal(t, 15*time.Minute, prefix.ttl)
+
+	// A request-level setting caches only the system instruction
+	prefix, ok = cachePrefixFor(&pb.LLMRequest{
+		Messages:     []*pb.ChatMessage{messages[0], messages[1]},
+		CacheControl: &pb.CacheControl{UseCache: true},
+	}, system, nil)
+	require.True(t, ok)
+	require.Empty(t, prefix.turns)
+	require.Equal(t, defaultCacheTTL, prefix.ttl)
+
+	// Nothing to cache without a breakpoint or a system instruction
+	_, ok = cachePrefixFor(&pb.LLMRequest{Messages: messages[3:4]}, nil, nil)
+	require.False(t, ok)
+	_, ok = cachePrefixFor(&pb.LLMRequest{
+		Messages:     messages[3:4],
+		CacheControl: &pb.CacheControl{UseCache: true},
+	}, nil, nil)
+	require.False(t, ok)
+}
+
+func TestCachePrefixKey(t *testing.T) {
+	system := &genai.Content{Parts: []genai.Part{genai.Text("ab")}}
+	prefix := cachePrefix{system: system, ttl: time.Minute}
+
+	require.Equal(t, prefix.key("gemini-test"), cachePrefix{system: system, ttl: time.Hour}.key("gemini-test"))
+	require.NotEqual(t, prefix.key("gemini-test"), prefix.key("gemini-other"))
+
+	// Part boundaries are part of the identity
+	split := cachePrefix{system: &genai.Content{Parts: []genai.Part{genai.Text("a"), genai.Text("b")}}}
+	require.NotEqual(t, prefix.key("gemini-test"), split.key("gemini-test"))
+}
@@ -3,6 +3,8 @@ package gemini
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strings"
 
 	"github.com/google/generative-ai-go/genai"
 	"google.golang.org/api/iterator"
@@ -12,10 +14,19 @@ import (
 	pb "github.com/c0rtexR/llm_service/proto"
 )
 
+// defaultBaseURL is the Gemini API endpoint used when no base URL is configured
+const defaultBaseURL = "https://generativelanguage.googleapis.com"
+
 type Provider struct {
-	client       *genai.Client
+	client *genai.Client
+	// httpClient calls the REST endpoints the SDK does not cover
+	httpClient   *http.Client
 	config       *provider.Config
 	defaultModel string
+	// safetySettings holds the operator's default thresholds by harm category
+	safetySettings map[string]string
+	// cache tracks the CachedContent resources created for prompt prefixes
+	cache *contextCache
 }
 
 // New creates a new Gemini provider
@@ -24,7 +35,27 @@ func New(config *provider.Config) (*Provider, error) {
 		return nil, fmt.Errorf("gemini: API key is required")
 	}
 
-	client, err := genai.NewClient(context.Background(), option.WithAPIKey(config.APIKey))
+	// A custom HTTP client bypasses the SDK's API key handling, so the transport adds the key
+	streamClient := config.NewStreamingHTTPClient()
+	streamClient.Transport = &apiKeyTransport{
+		apiKey: config.APIKey,
+		base:   streamClient.Transport,
+	}
+	httpClient := config.NewHTTPClient()
+	httpClient.Transport = &apiKeyTransport{
+		apiKey: config.APIKey,
+		base:   httpClient.Transport,
+	}
+
+	opts := []option.ClientOption{
+		option.WithAPIKey(config.APIKey),
+		option.WithHTTPClient(streamClient),
+	}
+	if config.BaseURL != "" {
+		opts = append(opts, option.WithEndpoint(config.BaseURL))
+	}
+
+	client, err := genai.NewClient(context.Background(), opts...)
 	if err != nil {
 		return nil, fmt.Errorf("gemini: failed to create client: %w", err)
 	}
@@ -36,78 +67,44 @@ func New(config *provider.Config) (*Provider, error) {
 
 	return &Provider{
 		client:       client,
+		httpClient:   httpClient,
 		config:       config,
 		defaultModel: defaultModel,
+		cache:        newContextCache(),
 	}, nil
 }
 
 // Invoke implements the LLMProvider interface
 func (p *Provider) Invoke(ctx context.Context, req *pb.LLMRequest) (*pb.LLMResponse, error) {
-	model := p.client.GenerativeModel(p.getModelName(req))
-
-	// Configure model parameters
-	if req.Temperature != 0 {
-		model.SetTemperature(float32(req.Temperature))
-	}
-	if req.TopP != 0 {
-		model.SetTopP(float32(req.TopP))
-	}
-	if req.TopK != 0 {
-		model.SetTopK(int32(req.TopK))
+	system, history, last, err := splitMessages(req.Messages)
+	if err != nil {
+		return nil, err
 	}
-	if req.MaxTokens != 0 {
-		model.SetMaxOutputTokens(int32(req.MaxTokens))
+
+	model, history, err := p.newModel(ctx, req, system, history)
+	if err != nil {
+		return nil, err
 	}
-	model.ResponseMIMEType = "text/plain"
 
-	// Start a chat session
+	// Earlier turns go into the session history; only the final user turn is sent
 	session := model.StartChat()
+	session.History = history
 
-	// Convert messages to Gemini format and add to history
-	for _, msg := range req.Messages {
-		content := &genai.Content{}
-
-		switch msg.Role {
-		case "user":
-			content.Role = "user"
-			content.Parts = []genai.Part{genai.Text(msg.Content)}
-		case "assistant":
-			content.Role = "model"
-			content.Parts = []genai.Part{genai.Text(msg.Content)}
-		case "system":
-			// For system messages, we'll add them as user messages since Gemini doesn't support system
-			content.Role = "user"
-			content.Parts = []genai.Part{genai.Text(msg.Content)}
-		}
-
-		session.History = append(session.History, content)
-	}
-
-	// Get the last user message to send
-	lastMsg := req.Messages[len(req.Messages)-1]
-	resp, err := session.SendMessage(ctx, genai.Text(lastMsg.Content))
+	resp, err := session.SendMessage(ctx, last.Parts...)
 	if err != nil {
+		if blocked := asBlockedError(err); blocked != nil {
+			return nil, blocked
+		}
 		return nil, fmt.Errorf("gemini: generate failed: %w", err)
 	}
 
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
 		return nil, fmt.Errorf("gemini: no response generated")
 	}
 
-	// Extract the response text
-	content := ""
-	for _, part := range resp.Candidates[0].Content.Parts {
-		if text, ok := part.(genai.Text); ok {
-			content += string(text)
-		}
-	}
-
 	return &pb.LLMResponse{
-		Content: content,
-		Usage: &pb.UsageInfo{
-			// Gemini doesn't provide token counts directly
-			TotalTokens: 0,
-		},
+		Content: candidateText(resp.Candidates[0]),
+		Usage:   convertUsage(resp.UsageMetadata),
 	}, nil
 }
 
@@ -120,58 +117,49 @@ func (p *Provider) InvokeStream(ctx context.Context, req *pb.LLMRequest) (<-chan
 		defer close(responseChan)
 		defer close(errorChan)
 
-		model := p.client.GenerativeModel(p.getModelName(req))
-
-		// Configure model parameters
-		if req.Temperature != 0 {
-			model.SetTemperature(float32(req.Temperature))
+		system, history, last, err := splitMessages(req.Messages)
+		if err != nil {
+			errorChan <- err
+			return
 		}
-		if req.TopP != 0 {
-			model.SetTopP(float32(req.TopP))
-		}
-		if req.TopK != 0 {
-			model.SetTopK(int32(req.TopK))
-		}
-		if req.MaxTokens != 0 {
-			model.SetMaxOutputTokens(int32(req.MaxTokens))
+
+		model, history, err := p.newModel(ctx, req, system, history)
+		if err != nil {
+			errorChan <- err
+			return
 		}
-		model.ResponseMIMEType = "text/plain"
 
-		// Start a chat session
 		session := model.StartChat()
+		session.History = history
 
-		// Convert messages to Gemini format and add to history
-		for _, msg := range req.Messages {
-			content := &genai.Content{}
-
-			switch msg.Role {
-			case "user":
-				content.Role = "user"
-				content.Parts = []genai.Part{genai.Text(msg.Content)}
-			case "assistant":
-				content.Role = "model"
-				content.Parts = []genai.Part{genai.Text(msg.Content)}
-			case "system":
-				// For system messages, we'll add them as user messages since Gemini doesn't support system
-				content.Role = "user"
-				content.Parts = []genai.Part{genai.Text(msg.Content)}
+		send := func(resp *pb.LLMStreamResponse) bool {
+			select {
+			case responseChan <- resp:
+				return true
+			case <-ctx.Done():
+				errorChan <- ctx.Err()
+				return false
 			}
-
-			session.History = append(session.History, content)
 		}
 
-		// Get the last user message to send
-		lastMsg := req.Messages[len(req.Messages)-1]
-		iter := session.SendMessageStream(ctx, genai.Text(lastMsg.Content))
+		iter := session.SendMessageStream(ctx, last.Parts...)
 
-		// Process the stream
+		// Usage metadata is cumulative, so the last chunk that carries it wins
+		var (
+			usage  *genai.UsageMetadata
+			reason genai.FinishReason
+		)
 		for {
 			resp, err := iter.Next()
 			if err == iterator.Done {
-				// End of stream
-				responseChan <- &pb.LLMStreamResponse{
+				break
+			}
+			if blocked := asBlockedError(err); blocked != nil {
+				if send(&pb.LLMStreamResponse{
 					Type:         pb.ResponseType_TYPE_FINISH_REASON,
-					FinishReason: "stop",
+					FinishReason: blocked.FinishReason(),
+				}) {
+					errorChan <- blocked
 				}
 				return
 			}
@@ -180,23 +168,185 @@ func (p *Provider) InvokeStream(ctx context.Context, req *pb.LLMRequest) (<-chan
 				return
 			}
 
-			// Process each candidate's content parts
-			for _, candidate := range resp.Candidates {
-				for _, part := range candidate.Content.Parts {
-					if text, ok := part.(genai.Text); ok && len(text) > 0 {
-						responseChan <- &pb.LLMStreamResponse{
-							Type:    pb.ResponseType_TYPE_CONTENT,
-							Content: string(text),
-						}
-					}
+			if resp.UsageMetadata != nil {
+				usage = resp.UsageMetadata
+			}
+			if len(resp.Candidates) == 0 {
+				continue
+			}
+
+			candidate := resp.Candidates[0]
+			if candidate.FinishReason != genai.FinishReasonUnspecified {
+				reason = candidate.FinishReason
+			}
+			if text := candidateText(candidate); text != "" {
+				if !send(&pb.LLMStreamResponse{
+					Type:    pb.ResponseType_TYPE_CONTENT,
+					Content: text,
+				}) {
+					return
 				}
 			}
 		}
+
+		if reason != genai.FinishReasonUnspecified {
+			if !send(&pb.LLMStreamResponse{
+				Type:         pb.ResponseType_TYPE_FINISH_REASON,
+				FinishReason: finishReason(reason),
+			}) {
+				return
+			}
+		}
+		if usage != nil {
+			send(&pb.LLMStreamResponse{
+				Type:  pb.ResponseType_TYPE_USAGE,
+				Usage: convertUsage(usage),
+			})
+		}
 	}()
 
 	return responseChan, errorChan
 }
 
+// newModel creates a generative model configured from the request parameters. When the
+// request asks for caching, the cached prefix is served from a CachedContent and the
+// returned history holds only the turns after it.
+func (p *Provider) newModel(ctx context.Context, req *pb.LLMRequest, system *genai.Content, history []*genai.Content) (*genai.GenerativeModel, []*genai.Content, error) {
+	safetySettings, err := p.mergeSafetySettings(req.SafetySettings)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	modelName := p.getModelName(req)
+	model := p.client.GenerativeModel(modelName)
+
+	if req.Temperature != 0 {
+		model.SetTemperature(req.Temperature)
+	}
+	if req.TopP != 0 {
+		model.SetTopP(req.TopP)
+	}
+	if req.TopK != 0 {
+		model.SetTopK(req.TopK)
+	}
+	if req.MaxTokens != 0 {
+		model.SetMaxOutputTokens(req.MaxTokens)
+	}
+	if len(req.Stop) > 0 {
+		model.StopSequences = req.Stop
+	}
+	model.ResponseMIMEType = "text/plain"
+	model.SystemInstruction = system
+	model.SafetySettings = safetySettings
+
+	if prefix, ok := cachePrefixFor(req, system, history); ok {
+		if name := p.cachedContent(ctx, modelName, prefix); name != "" {
+			// Gemini rejects a system instruction alongside cached content
+			model.CachedContentName = name
+			model.SystemInstruction = nil
+			history = history[len(prefix.turns):]
+		}
+	}
+
+	return model, history, nil
+}
+
+// splitMessages converts the conversation into Gemini's shape: system messages become
+// the system instruction, earlier turns become chat history, and the final message is
+// the user turn that gets sent.
+func splitMessages(messages []*pb.ChatMessage) (*genai.Content, []*genai.Content, *genai.Content, error) {
+	var system *genai.Content
+	var turns []*genai.Content
+
+	for _, msg := range messages {
+		switch msg.Role {
+		case "system":
+			if system == nil {
+				system = &genai.Content{}
+			}
+			system.Parts = append(system.Parts, genai.Text(msg.Content))
+		case "user":
+			turns = append(turns, &genai.Content{Role: "user", Parts: []genai.Part{genai.Text(msg.Content)}})
+		case "assistant":
+			turns = append(turns, &genai.Content{Role: "model", Parts: []genai.Part{genai.Text(msg.Content)}})
+		default:
+			return nil, nil, nil, fmt.Errorf("gemini: unsupported message role %q", msg.Role)
+		}
+	}
+
+	if len(turns) == 0 {
+		return nil, nil, nil, fmt.Errorf("gemini: at least one user message is required")
+	}
+	last := turns[len(turns)-1]
+	if last.Role != "user" {
+		return nil, nil, nil, fmt.Errorf("gemini: the last message must be from the user")
+	}
+
+	return system, turns[:len(turns)-1], last, nil
+}
+
+// candidateText concatenates the text parts of a candidate
+func candidateText(candidate *genai.Candidate) string {
+	if candidate.Content == nil {
+		return ""
+	}
+	var text strings.Builder
+	for _, part := range candidate.Content.Parts {
+		if t, ok := part.(genai.Text); ok {
+			text.WriteString(string(t))
+		}
+	}
+	return text.String()
+}
+
+// convertUsage converts Gemini usage metadata to the proto format
+func convertUsage(usage *genai.UsageMetadata) *pb.UsageInfo {
+	if usage == nil {
+		return &pb.UsageInfo{}
+	}
+	return &pb.UsageInfo{
+		PromptTokens:     usage.PromptTokenCount,
+		CompletionTokens: usage.CandidatesTokenCount,
+		TotalTokens:      usage.TotalTokenCount,
+		CacheReadTokens:  usage.CachedContentTokenCount,
+	}
+}
+
+// finishReason maps Gemini finish reasons to the OpenAI-style values used by the service
+func finishReason(reason genai.FinishReason) string {
+	switch reason {
+	case genai.FinishReasonStop:
+		return "stop"
+	case genai.FinishReasonMaxTokens:
+		return "length"
+	case genai.FinishReasonSafety, genai.FinishReasonRecitation:
+		return "content_filter"
+	default:
+		return "other"
+	}
+}
+
+// apiKeyTransport adds the API key header to every request
+type apiKeyTransport struct {
+	apiKey string
+	base   http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *apiKeyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("x-goog-api-key", t.apiKey)
+	return t.base.RoundTrip(req)
+}
+
+// baseURL returns the API endpoint, as the SDK resolves it
+func (p *Provider) baseURL() string {
+	if p.config.BaseURL != "" {
+		return strings.TrimSuffix(p.config.BaseURL, "/")
+	}
+	return defaultBaseURL
+}
+
 // getModelName returns the model name to use, falling back to default if not specified
 func (p *Provider) getModelName(req *pb.LLMRequest) string {
 	if req.Model != "" {
@@ -2,9 +2,13 @@ package gemini
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/google/generative-ai-go/genai"
 	"github.com/stretchr/testify/require"
 
 	"github.com/c0rtexR/llm_service/internal/provider"
@@ -49,6 +53,123 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestRequestsUseConfiguredTransport(t *testing.T) {
+	var gotKey string
+	var gotBody struct {
+		SystemInstruction *struct {
+			Parts []struct {
+				Text string `json:"text"`
+			} `json:"parts"`
+		} `json:"systemInstruction"`
+		Contents []struct {
+			Role string `json:"role"`
+		} `json:"contents"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("x-goog-api-key")
+		require.Equal(t, "/v1beta/models/gemini-test:streamGenerateContent", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	p, err := New(provider.NewConfig("test-key", "gemini-test").WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	_, err = p.Invoke(context.Background(), &pb.LLMRequest{
+		Messages: []*pb.ChatMessage{
+			{Role: "system", Content: "Be brief."},
+			{Role: "user", Content: "Hello"},
+		},
+	})
+	require.ErrorContains(t, err, "503")
+
+	// The custom HTTP client must still authenticate
+	require.Equal(t, "test-key", gotKey)
+	require.NotNil(t, gotBody.SystemInstruction)
+	require.Equal(t, "Be brief.", gotBody.SystemInstruction.Parts[0].Text)
+	require.Len(t, gotBody.Contents, 1)
+}
+
+func TestSplitMessages(t *testing.T) {
+	system, history, last, err := splitMessages([]*pb.ChatMessage{
+		{Role: "system", Content: "You are a helpful assistant."},
+		{Role: "user", Content: "What is 2+2?"},
+		{Role: "assistant", Content: "2+2 equals 4"},
+		{Role: "system", Content: "Answer briefly."},
+		{Role: "user", Content: "What did I ask you?"},
+	})
+	require.NoError(t, err)
+
+	// System messages are collected into the system instruction
+	require.Equal(t, []genai.Part{genai.Text("You are a helpful assistant."), genai.Text("Answer briefly.")}, system.Parts)
+
+	// The final user turn is sent, not duplicated in history
+	require.Len(t, history, 2)
+	require.Equal(t, "user", history[0].Role)
+	require.Equal(t, "model", history[1].Role)
+	require.Equal(t, "user", last.Role)
+	require.Equal(t, []genai.Part{genai.Text("What did I ask you?")}, last.Parts)
+
+	// No system messages means no system instruction
+	system, history, _, err = splitMessages([]*pb.ChatMessage{{Role: "user", Content: "Hi"}})
+	require.NoError(t, err)
+	require.Nil(t, system)
+	require.Empty(t, history)
+}
+
+func TestSplitMessagesValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []*pb.ChatMessage
+		errMsg   string
+	}{
+		{name: "empty", messages: nil, errMsg: "at least one user message"},
+		{name: "system only", messages: []*pb.ChatMessage{{Role: "system", Content: "Be nice."}}, errMsg: "at least one user message"},
+		{name: "ends with assistant", messages: []*pb.ChatMessage{{Role: "user", Content: "Hi"}, {Role: "assistant", Content: "Hello"}}, errMsg: "last message must be from the user"},
+		{name: "unknown role", messages: []*pb.ChatMessage{{Role: "tool", Content: "{}"}}, errMsg: "unsupported message role"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := splitMessages(tt.messages)
+			require.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+
+	// Empty requests are rejected before anything is sent
+	p, err := New(&provider.Config{APIKey: "test-key"})
+	require.NoError(t, err)
+
+	_, err = p.Invoke(context.Background(), &pb.LLMRequest{})
+	require.ErrorContains(t, err, "at least one user message")
+
+	respChan, errChan := p.InvokeStream(context.Background(), &pb.LLMRequest{})
+	require.ErrorContains(t, <-errChan, "at least one user message")
+	_, ok := <-respChan
+	require.False(t, ok)
+}
+
+func TestConvertUsageAndFinishReason(t *testing.T) {
+	usage := convertUsage(&genai.UsageMetadata{
+		PromptTokenCount:        12,
+		CachedContentTokenCount: 4,
+		CandidatesTokenCount:    30,
+		TotalTokenCount:         42,
+	})
+	require.Equal(t, int32(12), usage.PromptTokens)
+	require.Equal(t, int32(30), usage.CompletionTokens)
+	require.Equal(t, int32(42), usage.TotalTokens)
+	require.Equal(t, int32(4), usage.CacheReadTokens)
+	require.NotNil(t, convertUsage(nil))
+
+	require.Equal(t, "stop", finishReason(genai.FinishReasonStop))
+	require.Equal(t, "length", finishReason(genai.FinishReasonMaxTokens))
+	require.Equal(t, "content_filter", finishReason(genai.FinishReasonSafety))
+	require.Equal(t, "content_filter", finishReason(genai.FinishReasonRecitation))
+	require.Equal(t, "other", finishReason(genai.FinishReasonOther))
+}
+
 func TestProvider_Invoke(t *testing.T) {
 	apiKey := os.Getenv("GEMINI_API_KEY")
 	if apiKey == "" {
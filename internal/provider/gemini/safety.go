@@ -0,0 +1,256 @@
+package gemini
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// contentFilterReason is the ErrorInfo reason of a blocked generation
+const contentFilterReason = "CONTENT_FILTER"
+
+// harmCategories maps the API names of the harm categories Gemini accepts
+var harmCategories = map[string]genai.HarmCategory{
+	"HARM_CATEGORY_HARASSMENT":        genai.HarmCategoryHarassment,
+	"HARM_CATEGORY_HATE_SPEECH":       genai.HarmCategoryHateSpeech,
+	"HARM_CATEGORY_SEXUALLY_EXPLICIT": genai.HarmCategorySexuallyExplicit,
+	"HARM_CATEGORY_DANGEROUS_CONTENT": genai.HarmCategoryDangerousContent,
+}
+
+// blockThresholds maps the API names of the block thresholds
+var blockThresholds = map[string]genai.HarmBlockThreshold{
+	"BLOCK_LOW_AND_ABOVE":    genai.HarmBlockLowAndAbove,
+	"BLOCK_MEDIUM_AND_ABOVE": genai.HarmBlockMediumAndAbove,
+	"BLOCK_ONLY_HIGH":        genai.HarmBlockOnlyHigh,
+	"BLOCK_NONE":             genai.HarmBlockNone,
+}
+
+// harmProbabilities maps probabilities back to their API names
+var harmProbabilities = map[genai.HarmProbability]string{
+	genai.HarmProbabilityNegligible: "NEGLIGIBLE",
+	genai.HarmProbabilityLow:        "LOW",
+	genai.HarmProbabilityMedium:     "MEDIUM",
+	genai.HarmProbabilityHigh:       "HIGH",
+}
+
+// SafetyRating is the rating Gemini assigned to one harm category
+type SafetyRating struct {
+	Category    string
+	Probability string
+	Blocked     bool
+}
+
+// BlockedError is returned when Gemini blocks the prompt or the generated candidate.
+// BlockReason is the prompt block reason (SAFETY, OTHER) or, for a blocked candidate,
+// its finish reason (SAFETY, RECITATION).
+type BlockedError struct {
+	BlockReason   string
+	SafetyRatings []SafetyRating
+	// Prompt reports whether the prompt, rather than the response, was blocked
+	Prompt bool
+}
+
+// Error implements the error interface
+func (e *BlockedError) Error() string {
+	target := "response"
+	if e.Prompt {
+		target = "prompt"
+	}
+
+	var blocked []string
+	for _, rating := range e.SafetyRatings {
+		if rating.Blocked {
+			blocked = append(blocked, fmt.Sprintf("%s=%s", rating.Category, rating.Probability))
+		}
+	}
+	if len(blocked) == 0 {
+		return fmt.Sprintf("gemini: %s blocked: %s", target, e.BlockReason)
+	}
+	return fmt.Sprintf("gemini: %s blocked: %s (%s)", target, e.BlockReason, strings.Join(blocked, ", "))
+}
+
+// GRPCStatus returns the status sent to the client: FailedPrecondition with an ErrorInfo
+// whose reason is CONTENT_FILTER. Its metadata holds the block reason, what was blocked
+// and each rated category's probability, with "blocked_categories" listing the
+// categories that caused the block.
+func (e *BlockedError) GRPCStatus() *status.Status {
+	target := "response"
+	if e.Prompt {
+		target = "prompt"
+	}
+	metadata := map[string]string{
+		"block_reason": e.BlockReason,
+		"blocked":      target,
+	}
+	var blocked []string
+	for _, rating := range e.SafetyRatings {
+		metadata[rating.Category] = rating.Probability
+		if rating.Blocked {
+			blocked = append(blocked, rating.Category)
+		}
+	}
+	if len(blocked) > 0 {
+		metadata["blocked_categories"] = strings.Join(blocked, ",")
+	}
+
+	st := status.New(codes.FailedPrecondition, e.Error())
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   contentFilterReason,
+		Domain:   "gemini",
+		Metadata: metadata,
+	})
+	if err != nil {
+		return st
+	}
+	return detailed
+}
+
+// FinishReason returns the finish reason reported for a blocked generation
+func (e *BlockedError) FinishReason() string {
+	return "content_filter"
+}
+
+// ParseSafetySettings parses a comma-separated list of CATEGORY=THRESHOLD pairs, as used
+// for the operator defaults, e.g. "HARM_CATEGORY_HARASSMENT=BLOCK_ONLY_HIGH"
+func ParseSafetySettings(spec string) (map[string]string, error) {
+	settings := make(map[string]string)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		category, threshold, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("gemini: invalid safety setting %q: expected CATEGORY=THRESHOLD", pair)
+		}
+		settings[strings.TrimSpace(category)] = strings.TrimSpace(threshold)
+	}
+
+	if _, err := convertSafetySettings(settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// WithSafetySettings sets default safety thresholds; per-request settings override them
+// category by category
+func (p *Provider) WithSafetySettings(settings map[string]string) *Provider {
+	p.safetySettings = settings
+	return p
+}
+
+// mergeSafetySettings merges the provider defaults with the request's overrides
+func (p *Provider) mergeSafetySettings(overrides map[string]string) ([]*genai.SafetySetting, error) {
+	if len(overrides) == 0 {
+		return convertSafetySettings(p.safetySettings)
+	}
+
+	merged := make(map[string]string, len(p.safetySettings)+len(overrides))
+	for category, threshold := range p.safetySettings {
+		merged[category] = threshold
+	}
+	for category, threshold := range overrides {
+		merged[category] = threshold
+	}
+	return convertSafetySettings(merged)
+}
+
+// convertSafetySettings validates category and threshold names and converts them to
+// the SDK format, sorted by category so requests are deterministic
+func convertSafetySettings(settings map[string]string) ([]*genai.SafetySetting, error) {
+	if len(settings) == 0 {
+		return nil, nil
+	}
+
+	categories := make([]string, 0, len(settings))
+	for category := range settings {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	result := make([]*genai.SafetySetting, 0, len(settings))
+	for _, category := range categories {
+		harmCategory, ok := harmCategories[category]
+		if !ok {
+			return nil, fmt.Errorf("gemini: unknown harm category %q", category)
+		}
+		threshold, ok := blockThresholds[settings[category]]
+		if !ok {
+			return nil, fmt.Errorf("gemini: unknown block threshold %q for %s", settings[category], category)
+		}
+		result = append(result, &genai.SafetySetting{
+			Category:  harmCategory,
+			Threshold: threshold,
+		})
+	}
+	return result, nil
+}
+
+// asBlockedError converts the SDK's blocked error into a BlockedError, or returns nil if
+// err is not a block
+func asBlockedError(err error) *BlockedError {
+	var sdkErr *genai.BlockedError
+	if !errors.As(err, &sdkErr) {
+		return nil
+	}
+
+	if sdkErr.PromptFeedback != nil && sdkErr.PromptFeedback.BlockReason != genai.BlockReasonUnspecified {
+		reason := "OTHER"
+		if sdkErr.PromptFeedback.BlockReason == genai.BlockReasonSafety {
+			reason = "SAFETY"
+		}
+		return &BlockedError{
+			BlockReason:   reason,
+			SafetyRatings: convertSafetyRatings(sdkErr.PromptFeedback.SafetyRatings),
+			Prompt:        true,
+		}
+	}
+
+	blocked := &BlockedError{BlockReason: "OTHER"}
+	if sdkErr.Candidate != nil {
+		switch sdkErr.Candidate.FinishReason {
+		case genai.FinishReasonSafety:
+			blocked.BlockReason = "SAFETY"
+		case genai.FinishReasonRecitation:
+			blocked.BlockReason = "RECITATION"
+		}
+		blocked.SafetyRatings = convertSafetyRatings(sdkErr.Candidate.SafetyRatings)
+	}
+	return blocked
+}
+
+// convertSafetyRatings converts SDK safety ratings to their API names
+func convertSafetyRatings(ratings []*genai.SafetyRating) []SafetyRating {
+	if len(ratings) == 0 {
+		return nil
+	}
+
+	categoryNames := make(map[genai.HarmCategory]string, len(harmCategories))
+	for name, category := range harmCategories {
+		categoryNames[category] = name
+	}
+
+	result := make([]SafetyRating, 0, len(ratings))
+	for _, rating := range ratings {
+		category, ok := categoryNames[rating.Category]
+		if !ok {
+			category = rating.Category.String()
+		}
+		probability, ok := harmProbabilities[rating.Probability]
+		if !ok {
+			probability = rating.Probability.String()
+		}
+		result = append(result, SafetyRating{
+			Category:    category,
+			Probability: probability,
+			Blocked:     rating.Blocked,
+		})
+	}
+	return result
+}
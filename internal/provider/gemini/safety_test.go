@@ -0,0 +1,110 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+func TestParseSafetySettings(t *testing.T) {
+	settings, err := ParseSafetySettings("HARM_CATEGORY_HARASSMENT=BLOCK_ONLY_HIGH, HARM_CATEGORY_HATE_SPEECH=BLOCK_NONE,")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"HARM_CATEGORY_HARASSMENT":  "BLOCK_ONLY_HIGH",
+		"HARM_CATEGORY_HATE_SPEECH": "BLOCK_NONE",
+	}, settings)
+
+	_, err = ParseSafetySettings("HARM_CATEGORY_HARASSMENT")
+	require.ErrorContains(t, err, "expected CATEGORY=THRESHOLD")
+
+	_, err = ParseSafetySettings("HARM_CATEGORY_UNKNOWN=BLOCK_NONE")
+	require.ErrorContains(t, err, "unknown harm category")
+
+	_, err = ParseSafetySettings("HARM_CATEGORY_HARASSMENT=BLOCK_SOMETIMES")
+	require.ErrorContains(t, err, "unknown block threshold")
+}
+
+func TestMergeSafetySettings(t *testing.T) {
+	p, err := New(&provider.Config{APIKey: "test-key"})
+	require.NoError(t, err)
+	p.WithSafetySettings(map[string]string{
+		"HARM_CATEGORY_HARASSMENT":        "BLOCK_ONLY_HIGH",
+		"HARM_CATEGORY_DANGEROUS_CONTENT": "BLOCK_MEDIUM_AND_ABOVE",
+	})
+
+	// Request settings override the defaults per category
+	settings, err := p.mergeSafetySettings(map[string]string{
+		"HARM_CATEGORY_HARASSMENT":  "BLOCK_NONE",
+		"HARM_CATEGORY_HATE_SPEECH": "BLOCK_LOW_AND_ABOVE",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*genai.SafetySetting{
+		{Category: genai.HarmCategoryDangerousContent, Threshold: genai.HarmBlockMediumAndAbove},
+		{Category: genai.HarmCategoryHarassment, Threshold: genai.HarmBlockNone},
+		{Category: genai.HarmCategoryHateSpeech, Threshold: genai.HarmBlockLowAndAbove},
+	}, settings)
+
+	// Invalid request settings are rejected before anything is sent
+	_, err = p.Invoke(context.Background(), &pb.LLMRequest{
+		Messages:       []*pb.ChatMessage{{Role: "user", Content: "Hi"}},
+		SafetySettings: map[string]string{"HARM_CATEGORY_HARASSMENT": "BLOCK_ALL"},
+	})
+	require.ErrorContains(t, err, "unknown block threshold")
+}
+
+func TestAsBlockedError(t *testing.T) {
+	require.Nil(t, asBlockedError(fmt.Errorf("network down")))
+
+	// A blocked prompt reports the block reason and its ratings
+	blocked := asBlockedError(fmt.Errorf("wrapped: %w", &genai.BlockedError{
+		PromptFeedback: &genai.PromptFeedback{
+			BlockReason: genai.BlockReasonSafety,
+			SafetyRatings: []*genai.SafetyRating{
+				{Category: genai.HarmCategoryHarassment, Probability: genai.HarmProbabilityHigh, Blocked: true},
+				{Category: genai.HarmCategoryHateSpeech, Probability: genai.HarmProbabilityNegligible},
+			},
+		},
+	}))
+	require.NotNil(t, blocked)
+	require.True(t, blocked.Prompt)
+	require.Equal(t, "SAFETY", blocked.BlockReason)
+	require.Equal(t, []SafetyRating{
+		{Category: "HARM_CATEGORY_HARASSMENT", Probability: "HIGH", Blocked: true},
+		{Category: "HARM_CATEGORY_HATE_SPEECH", Probability: "NEGLIGIBLE"},
+	}, blocked.SafetyRatings)
+	require.Equal(t, "gemini: prompt blocked: SAFETY (HARM_CATEGORY_HARASSMENT=HIGH)", blocked.Error())
+	require.Equal(t, "content_filter", blocked.FinishReason())
+
+	// The block reaches gRPC clients as a content filter with its ratings, even wrapped
+	st, ok := status.FromError(fmt.Errorf("provider error: %w", blocked))
+	require.True(t, ok)
+	require.Equal(t, codes.FailedPrecondition, st.Code())
+	require.Len(t, st.Details(), 1)
+	info := st.Details()[0].(*errdetails.ErrorInfo)
+	require.Equal(t, "CONTENT_FILTER", info.Reason)
+	require.Equal(t, map[string]string{
+		"block_reason":              "SAFETY",
+		"blocked":                   "prompt",
+		"blocked_categories":        "HARM_CATEGORY_HARASSMENT",
+		"HARM_CATEGORY_HARASSMENT":  "HIGH",
+		"HARM_CATEGORY_HATE_SPEECH": "NEGLIGIBLE",
+	}, info.Metadata)
+
+	// A blocked candidate reports its finish reason
+	blocked = asBlockedError(&genai.BlockedError{
+		Candidate: &genai.Candidate{FinishReason: genai.FinishReasonRecitation},
+	})
+	require.NotNil(t, blocked)
+	require.False(t, blocked.Prompt)
+	require.Equal(t, "RECITATION", blocked.BlockReason)
+	require.Equal(t, "gemini: response blocked: RECITATION", blocked.Error())
+}
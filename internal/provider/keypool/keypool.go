@@ -0,0 +1,349 @@
+// Package keypool provides an LLMProvider that spreads requests across several API keys
+// of the same provider, resting keys that hit their rate limit and disabling keys the
+// provider rejects.
+package keypool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+// Policy controls how rate-limited keys are rested
+type Policy struct {
+	// Cooldown is how long a rate-limited key rests when the provider gives no hint
+	Cooldown time.Duration
+
+	// MaxCooldown caps the provider's Retry-After hint; 0 means no cap
+	MaxCooldown time.Duration
+}
+
+// DefaultPolicy returns the cooldown policy used when none is configured
+func DefaultPolicy() Policy {
+	return Policy{
+		Cooldown:    30 * time.Second,
+		MaxCooldown: 5 * time.Minute,
+	}
+}
+
+// Key is one API key of a pool, with the provider instance that uses it
+type Key struct {
+	// ID identifies the key in logs and reports (see Redact)
+	ID string
+
+	// Provider sends requests with the key
+	Provider provider.LLMProvider
+}
+
+// Redact returns an identifier for an API key that is safe to log
+func Redact(apiKey string) string {
+	if len(apiKey) < 12 {
+		return "****"
+	}
+	return apiKey[:4] + "..." + apiKey[len(apiKey)-4:]
+}
+
+// key is the state and usage of a pooled key; guarded by the pool's lock
+type key struct {
+	index    int
+	id       string
+	provider provider.LLMProvider
+
+	inFlight      int
+	cooldownUntil time.Time
+	// disabledReason is set once the provider rejected the key
+	disabledReason string
+
+	requests         int64
+	failures         int64
+	rateLimited      int64
+	promptTokens     int64
+	completionTokens int64
+}
+
+// Pool is an LLMProvider that sends each request with the least-loaded usable key,
+// rotating among equally loaded ones. A request rejected because of its key (429, 401,
+// 403) is sent again with another key.
+type Pool struct {
+	name   string
+	policy Policy
+	now    func() time.Time
+
+	mu   sync.Mutex
+	keys []*key
+	// next is where the search for the least-loaded key starts, for round-robin
+	next int
+}
+
+// New creates a pool over keys, which must not be empty. name identifies the provider
+// in logs and reports.
+func New(name string, keys []Key, policy Policy) *Pool {
+	p := &Pool{
+		name:   name,
+		policy: policy,
+		now:    time.Now,
+	}
+	for i, k := range keys {
+		p.keys = append(p.keys, &key{index: i, id: k.ID, provider: k.Provider})
+	}
+	return p
+}
+
+// Unwrap returns the provider of the first key, e.g. for batches, which belong to the
+// key that created them
+func (p *Pool) Unwrap() provider.LLMProvider {
+	return p.keys[0].provider
+}
+
+// AsPool returns the first key pool in a chain of wrappers
+func AsPool(lp provider.LLMProvider) (*Pool, bool) {
+	for {
+		if pool, ok := lp.(*Pool); ok {
+			return pool, true
+		}
+		w, ok := lp.(provider.Wrapper)
+		if !ok {
+			return nil, false
+		}
+		lp = w.Unwrap()
+	}
+}
+
+// Invoke implements the LLMProvider interface
+func (p *Pool) Invoke(ctx context.Context, req *pb.LLMRequest) (*pb.LLMResponse, error) {
+	tried := make(map[*key]bool)
+	var lastErr error
+	for {
+		k, err := p.acquire(tried)
+		if err != nil {
+			if lastErr != nil {
+				return nil, lastErr
+			}
+			return nil, err
+		}
+
+		resp, err := k.provider.Invoke(ctx, req)
+		p.release(k, err)
+		if err == nil {
+			p.recordUsage(k, resp.Usage)
+			return resp, nil
+		}
+		if !keyFault(err) || ctx.Err() != nil {
+			return nil, err
+		}
+		tried[k] = true
+		lastErr = err
+	}
+}
+
+// InvokeStream implements the LLMProvider interface. A stream moves to another key
+// only if its key was rejected before any chunk was delivered.
+func (p *Pool) InvokeStream(ctx context.Context, req *pb.LLMRequest) (<-chan *pb.LLMStreamResponse, <-chan error) {
+	responseChan := make(chan *pb.LLMStreamResponse)
+	errorChan := make(chan error, 1)
+
+	go func() {
+		defer close(responseChan)
+		defer close(errorChan)
+
+		tried := make(map[*key]bool)
+		var lastErr error
+		for {
+			k, err := p.acquire(tried)
+			if err != nil {
+				if lastErr != nil {
+					err = lastErr
+				}
+				errorChan <- err
+				return
+			}
+
+			delivered, err := p.forward(ctx, k, req, responseChan)
+			p.release(k, err)
+			if err == nil {
+				return
+			}
+			if delivered || !keyFault(err) || ctx.Err() != nil {
+				errorChan <- err
+				return
+			}
+			tried[k] = true
+			lastErr = err
+		}
+	}()
+
+	return responseChan, errorChan
+}
+
+// forward runs one streaming attempt with k, relaying its chunks and recording its
+// usage. It reports whether any chunk reached the caller and the error the attempt
+// ended with.
+func (p *Pool) forward(ctx context.Context, k *key, req *pb.LLMRequest, out chan<- *pb.LLMStreamResponse) (delivered bool, err error) {
+	respChan, errChan := k.provider.InvokeStream(ctx, req)
+
+	for respChan != nil || errChan != nil {
+		select {
+		case resp, ok := <-respChan:
+			if !ok {
+				respChan = nil
+				continue
+			}
+			if resp.Usage != nil {
+				p.recordUsage(k, resp.Usage)
+			}
+			select {
+			case out <- resp:
+				delivered = true
+			case <-ctx.Done():
+				return delivered, ctx.Err()
+			}
+		case err, ok := <-errChan:
+			if !ok {
+				errChan = nil
+				continue
+			}
+			if err != nil {
+				return delivered, err
+			}
+		}
+	}
+	return delivered, nil
+}
+
+// acquire picks the least-loaded usable key not in tried. If every key is resting it
+// fails with a 429 that tells callers when the first one is usable again.
+func (p *Pool) acquire(tried map[*key]bool) (*key, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	var best *key
+	var earliest time.Time
+	for i := range p.keys {
+		k := p.keys[(p.next+i)%len(p.keys)]
+		switch {
+		case k.disabledReason != "" || tried[k]:
+		case now.Before(k.cooldownUntil):
+			if earliest.IsZero() || k.cooldownUntil.Before(earliest) {
+				earliest = k.cooldownUntil
+			}
+		case best == nil || k.inFlight < best.inFlight:
+			best = k
+		}
+	}
+
+	if best == nil {
+		if !earliest.IsZero() {
+			return nil, &provider.APIError{
+				StatusCode: http.StatusTooManyRequests,
+				Body:       fmt.Sprintf("all %s API keys are rate limited", p.name),
+				RetryAfter: earliest.Sub(now),
+			}
+		}
+		return nil, fmt.Errorf("no usable %s API key: all keys are disabled", p.name)
+	}
+
+	p.next = (best.index + 1) % len(p.keys)
+	best.inFlight++
+	best.requests++
+	return best, nil
+}
+
+// release records the outcome of a request sent with k
+func (p *Pool) release(k *key, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	k.inFlight--
+	if err == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	k.failures++
+
+	switch provider.StatusCode(err) {
+	case http.StatusTooManyRequests:
+		k.rateLimited++
+		wait := provider.RetryAfterHint(err)
+		if wait <= 0 {
+			wait = p.policy.Cooldown
+		}
+		if p.policy.MaxCooldown > 0 {
+			wait = min(wait, p.policy.MaxCooldown)
+		}
+		k.cooldownUntil = p.now().Add(wait)
+		zap.L().Warn("API key rate limited",
+			zap.String("provider", p.name),
+			zap.String("key", k.id),
+			zap.Duration("cooldown", wait))
+	case http.StatusUnauthorized, http.StatusForbidden:
+		if k.disabledReason == "" {
+			k.disabledReason = err.Error()
+			zap.L().Error("API key disabled",
+				zap.String("provider", p.name),
+				zap.String("key", k.id),
+				zap.Error(err))
+		}
+	}
+}
+
+// recordUsage adds a response's token usage to k
+func (p *Pool) recordUsage(k *key, usage *pb.UsageInfo) {
+	if usage == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	k.promptTokens += int64(usage.PromptTokens)
+	k.completionTokens += int64(usage.CompletionTokens)
+}
+
+// keyFault reports whether err is specific to the key, so another key may succeed
+func keyFault(err error) bool {
+	switch provider.StatusCode(err) {
+	case http.StatusTooManyRequests, http.StatusUnauthorized, http.StatusForbidden:
+		return true
+	}
+	return false
+}
+
+// Info returns the state and usage of every key in the pool
+func (p *Pool) Info() []*pb.APIKeyInfo {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	infos := make([]*pb.APIKeyInfo, len(p.keys))
+	for i, k := range p.keys {
+		info := &pb.APIKeyInfo{
+			Provider:         p.name,
+			Index:            int32(k.index),
+			Id:               k.id,
+			State:            pb.APIKeyState_API_KEY_STATE_ACTIVE,
+			InFlight:         int32(k.inFlight),
+			Requests:         k.requests,
+			Failures:         k.failures,
+			RateLimited:      k.rateLimited,
+			PromptTokens:     k.promptTokens,
+			CompletionTokens: k.completionTokens,
+			DisabledReason:   k.disabledReason,
+		}
+		switch {
+		case k.disabledReason != "":
+			info.State = pb.APIKeyState_API_KEY_STATE_DISABLED
+		case now.Before(k.cooldownUntil):
+			info.State = pb.APIKeyState_API_KEY_STATE_COOLING_DOWN
+			info.CooldownUntil = k.cooldownUntil.Unix()
+		}
+		infos[i] = info
+	}
+	return infos
+}
@@ -0,0 +1,253 @@
+package keypool
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+// fakeProvider stands in for a provider configured with one key. It fails every call
+// with err if set, otherwise it answers with its name and some usage.
+type fakeProvider struct {
+	name  string
+	err   error
+	calls atomic.Int32
+
+	// block, if set, holds calls until it is closed
+	block chan struct{}
+}
+
+func (f *fakeProvider) Invoke(ctx context.Context, req *pb.LLMRequest) (*pb.LLMResponse, error) {
+	f.calls.Add(1)
+	if f.block != nil {
+		<-f.block
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &pb.LLMResponse{
+		Content: f.name,
+		Usage:   &pb.UsageInfo{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	}, nil
+}
+
+func (f *fakeProvider) InvokeStream(ctx context.Context, req *pb.LLMRequest) (<-chan *pb.LLMStreamResponse, <-chan error) {
+	f.calls.Add(1)
+	responseChan := make(chan *pb.LLMStreamResponse)
+	errorChan := make(chan error, 1)
+
+	go func() {
+		defer close(responseChan)
+		defer close(errorChan)
+
+		if f.err != nil {
+			errorChan <- f.err
+			return
+		}
+		responseChan <- &pb.LLMStreamResponse{Type: pb.ResponseType_TYPE_CONTENT, Content: f.name}
+		responseChan <- &pb.LLMStreamResponse{
+			Type:  pb.ResponseType_TYPE_USAGE,
+			Usage: &pb.UsageInfo{PromptTokens: 7, CompletionTokens: 3, TotalTokens: 10},
+		}
+	}()
+
+	return responseChan, errorChan
+}
+
+// newTestPool returns a pool over the fakes with a controllable clock
+func newTestPool(fakes ...*fakeProvider) (*Pool, *time.Time) {
+	keys := make([]Key, len(fakes))
+	for i, f := range fakes {
+		keys[i] = Key{ID: f.name, Provider: f}
+	}
+	pool := New("openai", keys, Policy{Cooldown: 30 * time.Second, MaxCooldown: time.Minute})
+	now := time.Unix(1700000000, 0)
+	pool.now = func() time.Time { return now }
+	return pool, &now
+}
+
+func TestRedact(t *testing.T) {
+	require.Equal(t, "sk-p...c3d4", Redact("sk-proj-abcdefa1b2c3d4"))
+	require.Equal(t, "****", Redact("short"))
+}
+
+func TestRoundRobin(t *testing.T) {
+	a, b, c := &fakeProvider{name: "a"}, &fakeProvider{name: "b"}, &fakeProvider{name: "c"}
+	pool, _ := newTestPool(a, b, c)
+
+	var served []string
+	for i := 0; i < 6; i++ {
+		resp, err := pool.Invoke(context.Background(), &pb.LLMRequest{})
+		require.NoError(t, err)
+		served = append(served, resp.Content)
+	}
+	require.Equal(t, []string{"a", "b", "c", "a", "b", "c"}, served)
+
+	infos := pool.Info()
+	require.Len(t, infos, 3)
+	require.Equal(t, int64(2), infos[0].Requests)
+	require.Equal(t, int64(20), infos[0].PromptTokens)
+	require.Equal(t, int64(10), infos[0].CompletionTokens)
+}
+
+func TestLeastLoaded(t *testing.T) {
+	a, b := &fakeProvider{name: "a", block: make(chan struct{})}, &fakeProvider{name: "b"}
+	pool, _ := newTestPool(a, b)
+
+	// A slow request keeps key a busy, so new requests go to key b
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = pool.Invoke(context.Background(), &pb.LLMRequest{})
+	}()
+	require.Eventually(t, func() bool { return a.calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		resp, err := pool.Invoke(context.Background(), &pb.LLMRequest{})
+		require.NoError(t, err)
+		require.Equal(t, "b", resp.Content)
+	}
+	require.Equal(t, int32(1), pool.Info()[0].InFlight)
+
+	close(a.block)
+	<-done
+}
+
+func TestRateLimitedKeyCoolsDown(t *testing.T) {
+	rateLimited := &provider.APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: 10 * time.Second}
+	a, b := &fakeProvider{name: "a", err: rateLimited}, &fakeProvider{name: "b"}
+	pool, now := newTestPool(a, b)
+
+	// The request moves to key b at once
+	resp, err := pool.Invoke(context.Background(), &pb.LLMRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "b", resp.Content)
+
+	infos := pool.Info()
+	require.Equal(t, pb.APIKeyState_API_KEY_STATE_COOLING_DOWN, infos[0].State)
+	require.Equal(t, now.Add(10*time.Second).Unix(), infos[0].CooldownUntil)
+	require.Equal(t, int64(1), infos[0].RateLimited)
+
+	// Key a is skipped while it rests
+	for i := 0; i < 3; i++ {
+		_, err := pool.Invoke(context.Background(), &pb.LLMRequest{})
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(1), a.calls.Load())
+
+	// After the cooldown it is used again
+	*now = now.Add(11 * time.Second)
+	a.err = nil
+	require.Equal(t, pb.APIKeyState_API_KEY_STATE_ACTIVE, pool.Info()[0].State)
+	served := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		resp, err := pool.Invoke(context.Background(), &pb.LLMRequest{})
+		require.NoError(t, err)
+		served[resp.Content] = true
+	}
+	require.True(t, served["a"])
+}
+
+func TestAllKeysRateLimited(t *testing.T) {
+	rateLimited := &provider.APIError{StatusCode: http.StatusTooManyRequests}
+	a, b := &fakeProvider{name: "a", err: rateLimited}, &fakeProvider{name: "b", err: rateLimited}
+	pool, _ := newTestPool(a, b)
+
+	_, err := pool.Invoke(context.Background(), &pb.LLMRequest{})
+	require.ErrorIs(t, err, rateLimited)
+
+	// While every key rests the pool fails fast with the earliest cooldown as the hint
+	_, err = pool.Invoke(context.Background(), &pb.LLMRequest{})
+	require.ErrorContains(t, err, "all openai API keys are rate limited")
+	require.Equal(t, http.StatusTooManyRequests, provider.StatusCode(err))
+	require.Equal(t, 30*time.Second, provider.RetryAfterHint(err))
+	require.True(t, provider.IsRetryable(err))
+	require.Equal(t, int32(2), a.calls.Load()+b.calls.Load())
+}
+
+func TestRejectedKeyIsDisabled(t *testing.T) {
+	unauthorized := &provider.APIError{StatusCode: http.StatusUnauthorized, Body: "invalid x-api-key"}
+	a, b := &fakeProvider{name: "a", err: unauthorized}, &fakeProvider{name: "b"}
+	pool, now := newTestPool(a, b)
+
+	resp, err := pool.Invoke(context.Background(), &pb.LLMRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "b", resp.Content)
+
+	info := pool.Info()[0]
+	require.Equal(t, pb.APIKeyState_API_KEY_STATE_DISABLED, info.State)
+	require.Contains(t, info.DisabledReason, "invalid x-api-key")
+
+	// Disabled keys stay disabled
+	*now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		_, err := pool.Invoke(context.Background(), &pb.LLMRequest{})
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(1), a.calls.Load())
+
+	b.err = &provider.APIError{StatusCode: http.StatusForbidden}
+	_, err = pool.Invoke(context.Background(), &pb.LLMRequest{})
+	require.Equal(t, http.StatusForbidden, provider.StatusCode(err))
+	_, err = pool.Invoke(context.Background(), &pb.LLMRequest{})
+	require.ErrorContains(t, err, "all keys are disabled")
+}
+
+func TestOtherErrorsPassThrough(t *testing.T) {
+	overloaded := &provider.APIError{StatusCode: provider.StatusOverloaded}
+	a, b := &fakeProvider{name: "a", err: overloaded}, &fakeProvider{name: "b"}
+	pool, _ := newTestPool(a, b)
+
+	// Provider-wide failures are not the key's fault, so no other key is tried
+	_, err := pool.Invoke(context.Background(), &pb.LLMRequest{})
+	require.ErrorIs(t, err, overloaded)
+	require.Zero(t, b.calls.Load())
+
+	info := pool.Info()[0]
+	require.Equal(t, pb.APIKeyState_API_KEY_STATE_ACTIVE, info.State)
+	require.Equal(t, int64(1), info.Failures)
+}
+
+func TestInvokeStream(t *testing.T) {
+	rateLimited := &provider.APIError{StatusCode: http.StatusTooManyRequests}
+	a, b := &fakeProvider{name: "a", err: rateLimited}, &fakeProvider{name: "b"}
+	pool, _ := newTestPool(a, b)
+
+	respChan, errChan := pool.InvokeStream(context.Background(), &pb.LLMRequest{})
+	var content []string
+	for resp := range respChan {
+		content = append(content, resp.Content)
+	}
+	require.NoError(t, <-errChan)
+	require.Equal(t, []string{"b", ""}, content)
+
+	infos := pool.Info()
+	require.Equal(t, pb.APIKeyState_API_KEY_STATE_COOLING_DOWN, infos[0].State)
+	require.Equal(t, int64(7), infos[1].PromptTokens)
+	require.Equal(t, int64(3), infos[1].CompletionTokens)
+}
+
+func TestAsPool(t *testing.T) {
+	pool, _ := newTestPool(&fakeProvider{name: "a"})
+
+	found, ok := AsPool(wrapper{pool})
+	require.True(t, ok)
+	require.Same(t, pool, found)
+
+	_, ok = AsPool(&fakeProvider{})
+	require.False(t, ok)
+}
+
+// wrapper is a decorator around a provider, like the retry provider
+type wrapper struct {
+	provider.LLMProvider
+}
+
+func (w wrapper) Unwrap() provider.LLMProvider { return w.LLMProvider }
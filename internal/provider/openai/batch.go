@@ -0,0 +1,391 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+const (
+	batchEndpoint         = "/v1/chat/completions"
+	batchCompletionWindow = "24h"
+)
+
+// batchInputLine represents a single request line in the batch input JSONL file
+type batchInputLine struct {
+	CustomID string      `json:"custom_id"`
+	Method   string      `json:"method"`
+	URL      string      `json:"url"`
+	Body     requestBody `json:"body"`
+}
+
+// batchCreateBody represents the JSON structure for creating a batch
+type batchCreateBody struct {
+	InputFileID      string `json:"input_file_id"`
+	Endpoint         string `json:"endpoint"`
+	CompletionWindow string `json:"completion_window"`
+}
+
+// fileResponseBody represents the JSON structure for an uploaded file
+type fileResponseBody struct {
+	ID      string `json:"id"`
+	Purpose string `json:"purpose"`
+}
+
+// batchResponseBody represents the JSON structure for a batch object
+type batchResponseBody struct {
+	ID            string `json:"id"`
+	Status        string `json:"status"`
+	InputFileID   string `json:"input_file_id"`
+	OutputFileID  string `json:"output_file_id"`
+	ErrorFileID   string `json:"error_file_id"`
+	CreatedAt     int64  `json:"created_at"`
+	ExpiresAt     int64  `json:"expires_at"`
+	CompletedAt   int64  `json:"completed_at"`
+	FailedAt      int64  `json:"failed_at"`
+	ExpiredAt     int64  `json:"expired_at"`
+	CancelledAt   int64  `json:"cancelled_at"`
+	RequestCounts struct {
+		Total     int32 `json:"total"`
+		Completed int32 `json:"completed"`
+		Failed    int32 `json:"failed"`
+	} `json:"request_counts"`
+}
+
+// batchOutputLine represents a single line in a batch output or error file
+type batchOutputLine struct {
+	ID       string `json:"id"`
+	CustomID string `json:"custom_id"`
+	Response *struct {
+		StatusCode int             `json:"status_code"`
+		RequestID  string          `json:"request_id"`
+		Body       json.RawMessage `json:"body"`
+	} `json:"response"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// CreateBatch uploads the requests as a JSONL file and creates a batch from it
+func (p *Provider) CreateBatch(ctx context.Context, items []*pb.BatchRequestItem) (*pb.BatchJob, error) {
+	input, err := p.buildBatchInput(items)
+	if err != nil {
+		return nil, err
+	}
+
+	fileID, err := p.uploadBatchFile(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBody, err := json.Marshal(batchCreateBody{
+		InputFileID:      fileID,
+		Endpoint:         batchEndpoint,
+		CompletionWindow: batchCompletionWindow,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	respBody, err := p.doBatchRequest(ctx, "POST", "/batches", "application/json", bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, err
+	}
+	defer respBody.Close()
+
+	var batch batchResponseBody
+	if err := json.NewDecoder(respBody).Decode(&batch); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return convertBatch(&batch), nil
+}
+
+// GetBatch returns the current status of a batch
+func (p *Provider) GetBatch(ctx context.Context, batchID string) (*pb.BatchJob, error) {
+	batch, err := p.retrieveBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	return convertBatch(batch), nil
+}
+
+// BatchResults downloads the output and error files of an ended batch and
+// streams their lines as results
+func (p *Provider) BatchResults(ctx context.Context, batchID string) (<-chan *pb.BatchResult, <-chan error) {
+	resultChan := make(chan *pb.BatchResult)
+	errorChan := make(chan error, 1)
+
+	go func() {
+		defer close(resultChan)
+		defer close(errorChan)
+
+		batch, err := p.retrieveBatch(ctx, batchID)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		if batchStatus(batch.Status) != pb.BatchStatus_BATCH_STATUS_ENDED {
+			errorChan <- fmt.Errorf("batch %s has not ended (status: %s)", batchID, batch.Status)
+			return
+		}
+
+		for _, fileID := range []string{batch.OutputFileID, batch.ErrorFileID} {
+			if fileID == "" {
+				continue
+			}
+			if err := p.streamBatchFile(ctx, fileID, resultChan); err != nil {
+				errorChan <- err
+				return
+			}
+		}
+	}()
+
+	return resultChan, errorChan
+}
+
+// buildBatchInput renders the requests as a JSONL batch input file
+func (p *Provider) buildBatchInput(items []*pb.BatchRequestItem) ([]byte, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("batch must contain at least one request")
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if item.CustomId == "" {
+			return nil, fmt.Errorf("custom_id is required")
+		}
+		if seen[item.CustomId] {
+			return nil, fmt.Errorf("duplicate custom_id %q", item.CustomId)
+		}
+		seen[item.CustomId] = true
+
+		if item.Request == nil {
+			return nil, fmt.Errorf("request %q is empty", item.CustomId)
+		}
+
+		// Encode appends a newline after each value, producing one request per line
+		if err := encoder.Encode(batchInputLine{
+			CustomID: item.CustomId,
+			Method:   "POST",
+			URL:      batchEndpoint,
+			Body:     p.buildRequestBody(item.Request),
+		}); err != nil {
+			return nil, fmt.Errorf("failed to marshal request %q: %w", item.CustomId, err)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// uploadBatchFile uploads a JSONL batch input file and returns its file ID
+func (p *Provider) uploadBatchFile(ctx context.Context, input []byte) (string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	if err := writer.WriteField("purpose", "batch"); err != nil {
+		return "", fmt.Errorf("failed to build upload: %w", err)
+	}
+	part, err := writer.CreateFormFile("file", "batch.jsonl")
+	if err != nil {
+		return "", fmt.Errorf("failed to build upload: %w", err)
+	}
+	if _, err := part.Write(input); err != nil {
+		return "", fmt.Errorf("failed to build upload: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to build upload: %w", err)
+	}
+
+	respBody, err := p.doBatchRequest(ctx, "POST", "/files", writer.FormDataContentType(), &buf)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload batch file: %w", err)
+	}
+	defer respBody.Close()
+
+	var file fileResponseBody
+	if err := json.NewDecoder(respBody).Decode(&file); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	return file.ID, nil
+}
+
+// retrieveBatch fetches the raw batch object
+func (p *Provider) retrieveBatch(ctx context.Context, batchID string) (*batchResponseBody, error) {
+	if batchID == "" {
+		return nil, fmt.Errorf("batch ID is required")
+	}
+	// The ID is escaped into the path, so it cannot reach another endpoint
+	if batchID == "." || batchID == ".." {
+		return nil, fmt.Errorf("invalid batch ID %q", batchID)
+	}
+
+	respBody, err := p.doBatchRequest(ctx, "GET", "/batches/"+url.PathEscape(batchID), "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer respBody.Close()
+
+	var batch batchResponseBody
+	if err := json.NewDecoder(respBody).Decode(&batch); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &batch, nil
+}
+
+// streamBatchFile downloads a batch output or error file and sends each line as a result
+func (p *Provider) streamBatchFile(ctx context.Context, fileID string, resultChan chan<- *pb.BatchResult) error {
+	if fileID == "." || fileID == ".." {
+		return fmt.Errorf("invalid batch file ID %q", fileID)
+	}
+	respBody, err := p.doBatchRequest(ctx, "GET", "/files/"+url.PathEscape(fileID)+"/content", "", nil)
+	if err != nil {
+		return fmt.Errorf("failed to download batch file %s: %w", fileID, err)
+	}
+	defer respBody.Close()
+
+	// A decoder reads one JSON object per line without a line-length limit
+	decoder := json.NewDecoder(respBody)
+	for {
+		var line batchOutputLine
+		if err := decoder.Decode(&line); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("failed to parse batch result: %w", err)
+		}
+
+		select {
+		case resultChan <- convertBatchResult(&line):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// doBatchRequest sends an authenticated request to the Files or Batches API
+func (p *Provider) doBatchRequest(ctx context.Context, method, path, contentType string, body io.Reader) (io.ReadCloser, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, method, p.config.BaseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.config.APIKey))
+	if contentType != "" {
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, provider.NewAPIError(resp, respBody)
+	}
+
+	return resp.Body, nil
+}
+
+// batchStatus maps an OpenAI batch status to the proto batch status
+func batchStatus(status string) pb.BatchStatus {
+	switch status {
+	case "validating", "in_progress", "finalizing":
+		return pb.BatchStatus_BATCH_STATUS_IN_PROGRESS
+	case "cancelling":
+		return pb.BatchStatus_BATCH_STATUS_CANCELING
+	case "completed", "expired", "cancelled":
+		return pb.BatchStatus_BATCH_STATUS_ENDED
+	case "failed":
+		return pb.BatchStatus_BATCH_STATUS_FAILED
+	default:
+		return pb.BatchStatus_BATCH_STATUS_UNSPECIFIED
+	}
+}
+
+// convertBatch converts an OpenAI batch object to the proto format
+func convertBatch(batch *batchResponseBody) *pb.BatchJob {
+	job := &pb.BatchJob{
+		Id:       batch.ID,
+		Provider: "openai",
+		Status:   batchStatus(batch.Status),
+		RequestCounts: &pb.BatchRequestCounts{
+			Succeeded: batch.RequestCounts.Completed,
+			Errored:   batch.RequestCounts.Failed,
+		},
+		CreatedAt: batch.CreatedAt,
+		ExpiresAt: batch.ExpiresAt,
+	}
+
+	// Requests that were neither completed nor failed are still running,
+	// or were dropped when the batch expired or was cancelled
+	remaining := batch.RequestCounts.Total - batch.RequestCounts.Completed - batch.RequestCounts.Failed
+	switch batch.Status {
+	case "expired":
+		job.RequestCounts.Expired = remaining
+	case "cancelled":
+		job.RequestCounts.Canceled = remaining
+	default:
+		job.RequestCounts.Processing = remaining
+	}
+
+	for _, endedAt := range []int64{batch.CompletedAt, batch.FailedAt, batch.ExpiredAt, batch.CancelledAt} {
+		if endedAt != 0 {
+			job.EndedAt = endedAt
+			break
+		}
+	}
+
+	return job
+}
+
+// convertBatchResult converts a single output or error file line to the proto format
+func convertBatchResult(line *batchOutputLine) *pb.BatchResult {
+	result := &pb.BatchResult{
+		CustomId: line.CustomID,
+	}
+
+	if line.Error != nil {
+		result.Status = "errored"
+		result.Error = fmt.Sprintf("%s: %s", line.Error.Code, line.Error.Message)
+		return result
+	}
+	if line.Response == nil {
+		result.Status = "errored"
+		result.Error = "missing response"
+		return result
+	}
+	if line.Response.StatusCode != http.StatusOK {
+		result.Status = "errored"
+		result.Error = fmt.Sprintf("request failed with status %d: %s", line.Response.StatusCode, line.Response.Body)
+		return result
+	}
+
+	var response responseBody
+	if err := json.Unmarshal(line.Response.Body, &response); err != nil {
+		result.Status = "errored"
+		result.Error = fmt.Sprintf("failed to parse response: %v", err)
+		return result
+	}
+	llmResp, err := convertResponse(&response)
+	if err != nil {
+		result.Status = "errored"
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Status = "succeeded"
+	result.Response = llmResp
+	return result
+}
@@ -0,0 +1,179 @@
+package openai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+func TestCreateBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+
+		switch r.URL.Path {
+		case "/files":
+			require.NoError(t, r.ParseMultipartForm(1<<20))
+			require.Equal(t, "batch", r.FormValue("purpose"))
+
+			file, header, err := r.FormFile("file")
+			require.NoError(t, err)
+			require.Equal(t, "batch.jsonl", header.Filename)
+
+			var lines []batchInputLine
+			scanner := bufio.NewScanner(file)
+			for scanner.Scan() {
+				var line batchInputLine
+				require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+				lines = append(lines, line)
+			}
+			require.Len(t, lines, 2)
+			require.Equal(t, "req-1", lines[0].CustomID)
+			require.Equal(t, "POST", lines[0].Method)
+			require.Equal(t, batchEndpoint, lines[0].URL)
+			require.Equal(t, "gpt-4o-mini", lines[0].Body.Model)
+			require.Equal(t, "Hello", lines[0].Body.Messages[0].Content)
+			require.False(t, lines[0].Body.Stream)
+			require.Equal(t, "req-2", lines[1].CustomID)
+
+			fmt.Fprint(w, `{"id":"file-abc","object":"file","purpose":"batch"}`)
+		case "/batches":
+			var body batchCreateBody
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, "file-abc", body.InputFileID)
+			require.Equal(t, batchEndpoint, body.Endpoint)
+			require.Equal(t, "24h", body.CompletionWindow)
+
+			fmt.Fprint(w, `{"id":"batch_123","object":"batch","status":"validating","input_file_id":"file-abc","created_at":1714508499,"expires_at":1714594899,"request_counts":{"total":0,"completed":0,"failed":0}}`)
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("test-key", "gpt-4o-mini").WithBaseURL(server.URL))
+	job, err := p.CreateBatch(context.Background(), []*pb.BatchRequestItem{
+		{CustomId: "req-1", Request: &pb.LLMRequest{Messages: []*pb.ChatMessage{{Role: "user", Content: "Hello"}}}},
+		{CustomId: "req-2", Request: &pb.LLMRequest{Messages: []*pb.ChatMessage{{Role: "user", Content: "Bye"}}}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "batch_123", job.Id)
+	require.Equal(t, "openai", job.Provider)
+	require.Equal(t, pb.BatchStatus_BATCH_STATUS_IN_PROGRESS, job.Status)
+	require.Equal(t, int64(1714508499), job.CreatedAt)
+}
+
+func TestCreateBatchValidation(t *testing.T) {
+	p := New(provider.NewConfig("test-key", "gpt-4o-mini"))
+	req := &pb.LLMRequest{Messages: []*pb.ChatMessage{{Role: "user", Content: "Hello"}}}
+
+	_, err := p.CreateBatch(context.Background(), nil)
+	require.ErrorContains(t, err, "at least one request")
+
+	_, err = p.CreateBatch(context.Background(), []*pb.BatchRequestItem{{Request: req}})
+	require.ErrorContains(t, err, "custom_id is required")
+
+	_, err = p.CreateBatch(context.Background(), []*pb.BatchRequestItem{
+		{CustomId: "a", Request: req},
+		{CustomId: "a", Request: req},
+	})
+	require.ErrorContains(t, err, "duplicate custom_id")
+}
+
+func TestBatchResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/batches/batch_123":
+			fmt.Fprint(w, `{"id":"batch_123","object":"batch","status":"completed","output_file_id":"file-out","error_file_id":"file-err","created_at":1714508499,"completed_at":1714509000,"request_counts":{"total":3,"completed":2,"failed":1}}`)
+		case "/files/file-out/content":
+			io.WriteString(w, `{"id":"batch_req_1","custom_id":"req-1","response":{"status_code":200,"request_id":"r1","body":{"id":"chatcmpl-1","model":"gpt-4o-mini","choices":[{"message":{"role":"assistant","content":"Hi!"},"finish_reason":"stop"}],"usage":{"prompt_tokens":8,"completion_tokens":2,"total_tokens":10}}},"error":null}`+"\n")
+			io.WriteString(w, `{"id":"batch_req_2","custom_id":"req-2","response":{"status_code":400,"request_id":"r2","body":{"error":{"message":"bad request"}}},"error":null}`+"\n")
+		case "/files/file-err/content":
+			io.WriteString(w, `{"id":"batch_req_3","custom_id":"req-3","response":null,"error":{"code":"batch_expired","message":"This request could not be executed before the completion window expired."}}`+"\n")
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("test-key", "gpt-4o-mini").WithBaseURL(server.URL))
+
+	job, err := p.GetBatch(context.Background(), "batch_123")
+	require.NoError(t, err)
+	require.Equal(t, pb.BatchStatus_BATCH_STATUS_ENDED, job.Status)
+	require.Equal(t, int32(2), job.RequestCounts.Succeeded)
+	require.Equal(t, int32(1), job.RequestCounts.Errored)
+	require.Zero(t, job.RequestCounts.Processing)
+	require.Equal(t, int64(1714509000), job.EndedAt)
+
+	resultChan, errChan := p.BatchResults(context.Background(), "batch_123")
+	var results []*pb.BatchResult
+	for result := range resultChan {
+		results = append(results, result)
+	}
+	require.NoError(t, <-errChan)
+
+	require.Len(t, results, 3)
+	require.Equal(t, "req-1", results[0].CustomId)
+	require.Equal(t, "succeeded", results[0].Status)
+	require.Equal(t, "Hi!", results[0].Response.Content)
+	require.Equal(t, int32(10), results[0].Response.Usage.TotalTokens)
+	require.Equal(t, "req-2", results[1].CustomId)
+	require.Equal(t, "errored", results[1].Status)
+	require.Contains(t, results[1].Error, "status 400")
+	require.Equal(t, "req-3", results[2].CustomId)
+	require.Equal(t, "errored", results[2].Status)
+	require.Contains(t, results[2].Error, "batch_expired")
+}
+
+func TestBatchResultsNotEnded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"batch_123","object":"batch","status":"in_progress","request_counts":{"total":3,"completed":1,"failed":0}}`)
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("test-key", "gpt-4o-mini").WithBaseURL(server.URL))
+
+	job, err := p.GetBatch(context.Background(), "batch_123")
+	require.NoError(t, err)
+	require.Equal(t, int32(2), job.RequestCounts.Processing)
+
+	resultChan, errChan := p.BatchResults(context.Background(), "batch_123")
+	require.ErrorContains(t, <-errChan, "has not ended")
+	_, ok := <-resultChan
+	require.False(t, ok)
+}
+
+func TestBatchResultsEscapesIDs(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		if strings.HasPrefix(r.URL.Path, "/batches/") {
+			fmt.Fprint(w, `{"id":"batch_123","object":"batch","status":"completed","output_file_id":"file/../secret"}`)
+		}
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("test-key", "gpt-4o-mini").WithBaseURL(server.URL))
+
+	// Neither the client's batch ID nor a file ID can leave their endpoints
+	resultChan, errChan := p.BatchResults(context.Background(), "../files")
+	for range resultChan {
+	}
+	require.NoError(t, <-errChan)
+	require.Equal(t, []string{"/batches/..%2Ffiles", "/files/file%2F..%2Fsecret/content"}, paths)
+
+	_, err := p.GetBatch(context.Background(), "..")
+	require.ErrorContains(t, err, "invalid batch ID")
+	require.Len(t, paths, 2)
+}
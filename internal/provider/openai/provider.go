@@ -1,16 +1,16 @@
 package openai
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
 
 	"github.com/c0rtexR/llm_service/internal/provider"
+	"github.com/c0rtexR/llm_service/internal/sse"
 	pb "github.com/c0rtexR/llm_service/proto"
 )
 
@@ -19,20 +19,34 @@ const (
 	defaultModel   = "gpt-3.5-turbo"
 )
 
-// Provider implements the LLMProvider interface for OpenAI
+// errPreviousResponseID is returned when a chained request is sent to the Chat Completions API
+var errPreviousResponseID = errors.New("previous_response_id requires the Responses API")
+
+// Provider implements the LLMProvider interface for OpenAI.
+// By default it uses the Chat Completions API; use WithResponsesAPI to switch to /responses.
 type Provider struct {
-	config     *provider.Config
-	httpClient *http.Client
+	config       *provider.Config
+	httpClient   *http.Client
+	streamClient *http.Client
+	responsesAPI bool
 }
 
 // requestBody represents the JSON structure for OpenAI API requests
 type requestBody struct {
-	Model       string        `json:"model"`
-	Messages    []chatMessage `json:"messages"`
-	Stream      bool          `json:"stream,omitempty"`
-	Temperature *float32      `json:"temperature,omitempty"`
-	MaxTokens   *int32        `json:"max_tokens,omitempty"`
-	TopP        *float32      `json:"top_p,omitempty"`
+	Model               string         `json:"model"`
+	Messages            []chatMessage  `json:"messages"`
+	Stream              bool           `json:"stream,omitempty"`
+	StreamOptions       *streamOptions `json:"stream_options,omitempty"`
+	Temperature         *float32       `json:"temperature,omitempty"`
+	MaxTokens           *int32         `json:"max_tokens,omitempty"`
+	MaxCompletionTokens *int32         `json:"max_completion_tokens,omitempty"`
+	TopP                *float32       `json:"top_p,omitempty"`
+	ReasoningEffort     string         `json:"reasoning_effort,omitempty"`
+}
+
+// streamOptions configures streaming; include_usage adds a final chunk with token usage
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
 }
 
 // chatMessage represents a single message in the OpenAI format
@@ -87,44 +101,29 @@ func New(config *provider.Config) *Provider {
 	}
 
 	return &Provider{
-		config:     config,
-		httpClient: &http.Client{},
+		config:       config,
+		httpClient:   config.NewHTTPClient(),
+		streamClient: config.NewStreamingHTTPClient(),
 	}
 }
 
+// WithResponsesAPI switches the provider to the Responses API, which supports
+// previous_response_id chaining and reasoning summaries
+func (p *Provider) WithResponsesAPI() *Provider {
+	p.responsesAPI = true
+	return p
+}
+
 // Invoke implements the LLMProvider interface for synchronous requests
 func (p *Provider) Invoke(ctx context.Context, req *pb.LLMRequest) (*pb.LLMResponse, error) {
-	// Use model from request or fall back to default
-	model := req.Model
-	if model == "" {
-		model = p.config.DefaultModel
+	if p.responsesAPI {
+		return p.invokeResponses(ctx, req)
 	}
-
-	// Convert messages to OpenAI format
-	messages := make([]chatMessage, len(req.Messages))
-	for i, msg := range req.Messages {
-		messages[i] = chatMessage{
-			Role:    msg.Role,
-			Content: msg.Content,
-		}
+	if req.PreviousResponseId != "" {
+		return nil, errPreviousResponseID
 	}
 
-	// Prepare request body
-	body := requestBody{
-		Model:    model,
-		Messages: messages,
-	}
-
-	// Add optional parameters if provided
-	if req.Temperature != 0 {
-		body.Temperature = &req.Temperature
-	}
-	if req.MaxTokens != 0 {
-		body.MaxTokens = &req.MaxTokens
-	}
-	if req.TopP != 0 {
-		body.TopP = &req.TopP
-	}
+	body := p.buildRequestBody(req)
 
 	// Marshal request body
 	jsonBody, err := json.Marshal(body)
@@ -159,7 +158,7 @@ func (p *Provider) Invoke(ctx context.Context, req *pb.LLMRequest) (*pb.LLMRespo
 
 	// Check for error response
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, respBody)
+		return nil, provider.NewAPIError(resp, respBody)
 	}
 
 	// Parse response
@@ -168,24 +167,15 @@ func (p *Provider) Invoke(ctx context.Context, req *pb.LLMRequest) (*pb.LLMRespo
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	// Check if we have any choices
-	if len(response.Choices) == 0 {
-		return nil, fmt.Errorf("no completion choices in response")
-	}
-
-	// Convert to proto response
-	return &pb.LLMResponse{
-		Content: response.Choices[0].Message.Content,
-		Usage: &pb.UsageInfo{
-			PromptTokens:     response.Usage.PromptTokens,
-			CompletionTokens: response.Usage.CompletionTokens,
-			TotalTokens:      response.Usage.TotalTokens,
-		},
-	}, nil
+	return convertResponse(&response)
 }
 
 // InvokeStream implements the LLMProvider interface for streaming requests
 func (p *Provider) InvokeStream(ctx context.Context, req *pb.LLMRequest) (<-chan *pb.LLMStreamResponse, <-chan error) {
+	if p.responsesAPI {
+		return p.streamResponses(ctx, req)
+	}
+
 	responseChan := make(chan *pb.LLMStreamResponse)
 	errorChan := make(chan error, 1)
 
@@ -193,38 +183,15 @@ func (p *Provider) InvokeStream(ctx context.Context, req *pb.LLMRequest) (<-chan
 		defer close(responseChan)
 		defer close(errorChan)
 
-		// Use model from request or fall back to default
-		model := req.Model
-		if model == "" {
-			model = p.config.DefaultModel
-		}
-
-		// Convert messages to OpenAI format
-		messages := make([]chatMessage, len(req.Messages))
-		for i, msg := range req.Messages {
-			messages[i] = chatMessage{
-				Role:    msg.Role,
-				Content: msg.Content,
-			}
+		if req.PreviousResponseId != "" {
+			errorChan <- errPreviousResponseID
+			return
 		}
 
 		// Prepare request body
-		body := requestBody{
-			Model:    model,
-			Messages: messages,
-			Stream:   true,
-		}
-
-		// Add optional parameters if provided
-		if req.Temperature != 0 {
-			body.Temperature = &req.Temperature
-		}
-		if req.MaxTokens != 0 {
-			body.MaxTokens = &req.MaxTokens
-		}
-		if req.TopP != 0 {
-			body.TopP = &req.TopP
-		}
+		body := p.buildRequestBody(req)
+		body.Stream = true
+		body.StreamOptions = &streamOptions{IncludeUsage: true}
 
 		// Marshal request body
 		jsonBody, err := json.Marshal(body)
@@ -248,7 +215,7 @@ func (p *Provider) InvokeStream(ctx context.Context, req *pb.LLMRequest) (<-chan
 		httpReq.Header.Set("Accept", "text/event-stream")
 
 		// Send request
-		resp, err := p.httpClient.Do(httpReq)
+		resp, err := p.streamClient.Do(httpReq)
 		if err != nil {
 			errorChan <- fmt.Errorf("failed to send request: %w", err)
 			return
@@ -258,87 +225,153 @@ func (p *Provider) InvokeStream(ctx context.Context, req *pb.LLMRequest) (<-chan
 		// Check for error response
 		if resp.StatusCode != http.StatusOK {
 			body, _ := io.ReadAll(resp.Body)
-			errorChan <- fmt.Errorf("request failed with status %d: %s", resp.StatusCode, body)
+			errorChan <- provider.NewAPIError(resp, body)
 			return
 		}
 
-		// Create scanner to read SSE stream
-		scanner := bufio.NewScanner(resp.Body)
-		var usage *pb.UsageInfo
+		// Create decoder to read SSE stream
+		decoder := sse.NewDecoder(resp.Body)
 
-		// Read stream
-		for scanner.Scan() {
-			line := scanner.Text()
-			if line == "" {
-				continue
+		send := func(resp *pb.LLMStreamResponse) bool {
+			select {
+			case responseChan <- resp:
+				return true
+			case <-ctx.Done():
+				errorChan <- ctx.Err()
+				return false
 			}
+		}
 
-			// Remove "data: " prefix
-			if !strings.HasPrefix(line, "data: ") {
-				continue
+		// Read stream
+		for {
+			event, err := decoder.Next()
+			if err == io.EOF {
+				// A complete stream ends with [DONE]
+				errorChan <- fmt.Errorf("stream ended before [DONE]: %w", io.ErrUnexpectedEOF)
+				return
+			}
+			if err != nil {
+				errorChan <- fmt.Errorf("error reading stream: %w", err)
+				return
 			}
-			line = strings.TrimPrefix(line, "data: ")
 
 			// Check for stream end
-			if line == "[DONE]" {
-				if usage != nil {
-					responseChan <- &pb.LLMStreamResponse{
-						Type:  pb.ResponseType_TYPE_USAGE,
-						Usage: usage,
-					}
-				}
+			if event.Data == "[DONE]" {
 				return
 			}
 
 			// Parse response chunk
 			var chunk streamResponseBody
-			if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+			if err := json.Unmarshal([]byte(event.Data), &chunk); err != nil {
 				errorChan <- fmt.Errorf("failed to parse chunk: %w", err)
 				return
 			}
 
-			// Check if we have any choices
-			if len(chunk.Choices) == 0 {
-				continue
-			}
-
-			// If we have usage info, save it for the final message
+			// With include_usage the final chunk has no choices and carries the usage
 			if chunk.Usage != nil {
-				usage = &pb.UsageInfo{
-					PromptTokens:     chunk.Usage.PromptTokens,
-					CompletionTokens: chunk.Usage.CompletionTokens,
-					TotalTokens:      chunk.Usage.TotalTokens,
+				if !send(&pb.LLMStreamResponse{
+					Type: pb.ResponseType_TYPE_USAGE,
+					Usage: &pb.UsageInfo{
+						PromptTokens:     chunk.Usage.PromptTokens,
+						CompletionTokens: chunk.Usage.CompletionTokens,
+						TotalTokens:      chunk.Usage.TotalTokens,
+					},
+				}) {
+					return
 				}
-				continue
 			}
 
-			// Get content from delta
-			content := chunk.Choices[0].Delta.Content
-			if content == "" {
+			if len(chunk.Choices) == 0 {
 				continue
 			}
 
 			// Send content chunk
-			responseChan <- &pb.LLMStreamResponse{
-				Type:    pb.ResponseType_TYPE_CONTENT,
-				Content: content,
+			if content := chunk.Choices[0].Delta.Content; content != "" {
+				if !send(&pb.LLMStreamResponse{
+					Type:    pb.ResponseType_TYPE_CONTENT,
+					Content: content,
+				}) {
+					return
+				}
 			}
 
-			// Check for finish reason
+			// The finish reason usually arrives on a chunk with an empty delta
 			if chunk.Choices[0].FinishReason != "" {
-				responseChan <- &pb.LLMStreamResponse{
+				if !send(&pb.LLMStreamResponse{
 					Type:         pb.ResponseType_TYPE_FINISH_REASON,
 					FinishReason: chunk.Choices[0].FinishReason,
+					ResponseId:   chunk.ID,
+				}) {
+					return
 				}
 			}
 		}
-
-		// Check for scanner errors
-		if err := scanner.Err(); err != nil {
-			errorChan <- fmt.Errorf("error reading stream: %w", err)
-			return
-		}
 	}()
 
 	return responseChan, errorChan
 }
+
+// buildRequestBody converts an LLMRequest into the OpenAI request format
+func (p *Provider) buildRequestBody(req *pb.LLMRequest) requestBody {
+	// Use model from request or fall back to default
+	model := req.Model
+	if model == "" {
+		model = p.config.DefaultModel
+	}
+
+	// Convert messages to OpenAI format
+	messages := make([]chatMessage, len(req.Messages))
+	for i, msg := range req.Messages {
+		messages[i] = chatMessage{
+			Role:    msg.Role,
+			Content: msg.Content,
+		}
+	}
+
+	// Prepare request body
+	body := requestBody{
+		Model:    model,
+		Messages: messages,
+	}
+
+	// Reasoning models take max_completion_tokens and reasoning_effort, and reject
+	// max_tokens and non-default sampling parameters
+	if isReasoningModel(model) {
+		if req.MaxTokens != 0 {
+			body.MaxCompletionTokens = &req.MaxTokens
+		}
+		body.ReasoningEffort = req.ReasoningEffort
+		return body
+	}
+
+	// Add optional parameters if provided
+	if req.Temperature != 0 {
+		body.Temperature = &req.Temperature
+	}
+	if req.MaxTokens != 0 {
+		body.MaxTokens = &req.MaxTokens
+	}
+	if req.TopP != 0 {
+		body.TopP = &req.TopP
+	}
+
+	return body
+}
+
+// convertResponse converts an OpenAI response into the proto response format
+func convertResponse(response *responseBody) (*pb.LLMResponse, error) {
+	// Check if we have any choices
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("no completion choices in response")
+	}
+
+	return &pb.LLMResponse{
+		Id:      response.ID,
+		Content: response.Choices[0].Message.Content,
+		Usage: &pb.UsageInfo{
+			PromptTokens:     response.Usage.PromptTokens,
+			CompletionTokens: response.Usage.CompletionTokens,
+			TotalTokens:      response.Usage.TotalTokens,
+		},
+	}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -151,6 +152,8 @@ func TestInvokeStream(t *testing.T) {
 		// Verify request body
 		require.Equal(t, "test-model", reqBody.Model)
 		require.True(t, reqBody.Stream)
+		require.NotNil(t, reqBody.StreamOptions)
+		require.True(t, reqBody.StreamOptions.IncludeUsage)
 		require.Len(t, reqBody.Messages, 1)
 		require.Equal(t, "user", reqBody.Messages[0].Role)
 		require.Equal(t, "test message", reqBody.Messages[0].Content)
@@ -161,10 +164,11 @@ func TestInvokeStream(t *testing.T) {
 
 		// Write response chunks
 		chunks := []string{
-			`{"id":"1","model":"test-model","choices":[{"delta":{"role":"assistant","content":"Hello"},"finish_reason":null}]}`,
-			`{"id":"2","model":"test-model","choices":[{"delta":{"content":" world"},"finish_reason":null}]}`,
-			`{"id":"3","model":"test-model","choices":[{"delta":{"content":"!"},"finish_reason":"stop"}]}`,
-			`{"id":"4","model":"test-model","choices":[{"delta":{},"finish_reason":"stop"}],"usage":{"prompt_tokens":10,"completion_tokens":20,"total_tokens":30}}`,
+			`{"id":"chatcmpl-1","model":"test-model","choices":[{"delta":{"role":"assistant","content":"Hello"},"finish_reason":null}],"usage":null}`,
+			`{"id":"chatcmpl-1","model":"test-model","choices":[{"delta":{"content":" world"},"finish_reason":null}],"usage":null}`,
+			`{"id":"chatcmpl-1","model":"test-model","choices":[{"delta":{"content":"!"},"finish_reason":null}],"usage":null}`,
+			`{"id":"chatcmpl-1","model":"test-model","choices":[{"delta":{},"finish_reason":"stop"}],"usage":null}`,
+			`{"id":"chatcmpl-1","model":"test-model","choices":[],"usage":{"prompt_tokens":10,"completion_tokens":20,"total_tokens":30}}`,
 		}
 
 		flusher, ok := w.(http.Flusher)
@@ -225,6 +229,7 @@ func TestInvokeStream(t *testing.T) {
 	// Fourth chunk should be finish reason
 	require.Equal(t, pb.ResponseType_TYPE_FINISH_REASON, responses[3].Type)
 	require.Equal(t, "stop", responses[3].FinishReason)
+	require.Equal(t, "chatcmpl-1", responses[3].ResponseId)
 
 	// Fifth chunk should be usage info
 	require.Equal(t, pb.ResponseType_TYPE_USAGE, responses[4].Type)
@@ -234,6 +239,29 @@ func TestInvokeStream(t *testing.T) {
 	require.Equal(t, int32(30), responses[4].Usage.TotalTokens)
 }
 
+func TestInvokeStreamTruncated(t *testing.T) {
+	// The connection closes before the finish reason and [DONE]
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"id\":\"chatcmpl-1\",\"choices\":[{\"delta\":{\"content\":\"Hello\"}}]}\n\n")
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("test-key", defaultModel).WithBaseURL(server.URL))
+	respChan, errChan := p.InvokeStream(context.Background(), &pb.LLMRequest{
+		Messages: []*pb.ChatMessage{{Role: "user", Content: "test message"}},
+	})
+
+	var content []string
+	for resp := range respChan {
+		content = append(content, resp.Content)
+	}
+	require.Equal(t, []string{"Hello"}, content)
+	err := <-errChan
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.True(t, provider.IsRetryable(err))
+}
+
 func TestInvokeStreamError(t *testing.T) {
 	// Create a test server that returns an error
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -266,3 +294,36 @@ func TestInvokeStreamError(t *testing.T) {
 	_, ok := <-respChan
 	require.False(t, ok)
 }
+
+func TestBuildRequestBodyModelFamilies(t *testing.T) {
+	p := New(provider.NewConfig("test-key", defaultModel))
+	req := &pb.LLMRequest{
+		Messages:        []*pb.ChatMessage{{Role: "user", Content: "Hi"}},
+		Temperature:     0.2,
+		TopP:            0.9,
+		MaxTokens:       512,
+		ReasoningEffort: "high",
+	}
+
+	// Chat models take max_tokens and sampling parameters, but not reasoning_effort
+	req.Model = "gpt-4o"
+	body := p.buildRequestBody(req)
+	require.Equal(t, int32(512), *body.MaxTokens)
+	require.Nil(t, body.MaxCompletionTokens)
+	require.Equal(t, float32(0.2), *body.Temperature)
+	require.Equal(t, float32(0.9), *body.TopP)
+	require.Empty(t, body.ReasoningEffort)
+
+	// Reasoning models take max_completion_tokens and reasoning_effort only
+	req.Model = "o3-mini"
+	body = p.buildRequestBody(req)
+	require.Nil(t, body.MaxTokens)
+	require.Equal(t, int32(512), *body.MaxCompletionTokens)
+	require.Nil(t, body.Temperature)
+	require.Nil(t, body.TopP)
+	require.Equal(t, "high", body.ReasoningEffort)
+
+	data, err := json.Marshal(body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"model":"o3-mini","messages":[{"role":"user","content":"Hi"}],"max_completion_tokens":512,"reasoning_effort":"high"}`, string(data))
+}
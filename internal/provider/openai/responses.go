@@ -0,0 +1,339 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	"github.com/c0rtexR/llm_service/internal/sse"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+// responsesRequestBody represents the JSON structure for Responses API requests
+type responsesRequestBody struct {
+	Model              string           `json:"model"`
+	Input              []chatMessage    `json:"input"`
+	PreviousResponseID string           `json:"previous_response_id,omitempty"`
+	Stream             bool             `json:"stream,omitempty"`
+	Temperature        *float32         `json:"temperature,omitempty"`
+	TopP               *float32         `json:"top_p,omitempty"`
+	MaxOutputTokens    *int32           `json:"max_output_tokens,omitempty"`
+	Reasoning          *reasoningConfig `json:"reasoning,omitempty"`
+}
+
+// reasoningConfig controls reasoning behavior for o-series models
+type reasoningConfig struct {
+	Effort  string `json:"effort,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+// responsesResponseBody represents the JSON structure for Responses API responses
+type responsesResponseBody struct {
+	ID                string                `json:"id"`
+	Status            string                `json:"status"`
+	Model             string                `json:"model"`
+	Output            []responsesOutputItem `json:"output"`
+	IncompleteDetails *struct {
+		Reason string `json:"reason"`
+	} `json:"incomplete_details,omitempty"`
+	Error *responsesError `json:"error,omitempty"`
+	Usage *responsesUsage `json:"usage,omitempty"`
+}
+
+// responsesOutputItem represents a message or reasoning item in the response output
+type responsesOutputItem struct {
+	Type    string `json:"type"`
+	ID      string `json:"id"`
+	Role    string `json:"role,omitempty"`
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content,omitempty"`
+	Summary []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"summary,omitempty"`
+}
+
+// responsesError describes a failed response
+type responsesError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// responsesUsage represents token usage reported by the Responses API
+type responsesUsage struct {
+	InputTokens  int32 `json:"input_tokens"`
+	OutputTokens int32 `json:"output_tokens"`
+	TotalTokens  int32 `json:"total_tokens"`
+}
+
+// responsesStreamEvent represents a single typed event in the Responses API SSE stream
+type responsesStreamEvent struct {
+	Type     string                 `json:"type"`
+	Delta    string                 `json:"delta,omitempty"`
+	Response *responsesResponseBody `json:"response,omitempty"`
+	Code     string                 `json:"code,omitempty"`
+	Message  string                 `json:"message,omitempty"`
+}
+
+// invokeResponses sends a non-streaming Responses API request
+func (p *Provider) invokeResponses(ctx context.Context, req *pb.LLMRequest) (*pb.LLMResponse, error) {
+	respBody, err := p.postResponses(ctx, p.buildResponsesRequestBody(req, false))
+	if err != nil {
+		return nil, err
+	}
+	defer respBody.Close()
+
+	var response responsesResponseBody
+	if err := json.NewDecoder(respBody).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if response.Error != nil {
+		return nil, fmt.Errorf("response failed (%s): %s", response.Error.Code, response.Error.Message)
+	}
+
+	var content, reasoning strings.Builder
+	for _, item := range response.Output {
+		switch item.Type {
+		case "message":
+			for _, part := range item.Content {
+				if part.Type == "output_text" {
+					content.WriteString(part.Text)
+				}
+			}
+		case "reasoning":
+			for _, part := range item.Summary {
+				if part.Type == "summary_text" {
+					reasoning.WriteString(part.Text)
+				}
+			}
+		}
+	}
+
+	return &pb.LLMResponse{
+		Id:        response.ID,
+		Content:   content.String(),
+		Reasoning: reasoning.String(),
+		Usage:     convertResponsesUsage(response.Usage),
+	}, nil
+}
+
+// streamResponses consumes the typed Responses API SSE events
+func (p *Provider) streamResponses(ctx context.Context, req *pb.LLMRequest) (<-chan *pb.LLMStreamResponse, <-chan error) {
+	responseChan := make(chan *pb.LLMStreamResponse)
+	errorChan := make(chan error, 1)
+
+	go func() {
+		defer close(responseChan)
+		defer close(errorChan)
+
+		respBody, err := p.postResponses(ctx, p.buildResponsesRequestBody(req, true))
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		defer respBody.Close()
+
+		decoder := sse.NewDecoder(respBody)
+
+		send := func(resp *pb.LLMStreamResponse) bool {
+			select {
+			case responseChan <- resp:
+				return true
+			case <-ctx.Done():
+				errorChan <- ctx.Err()
+				return false
+			}
+		}
+
+		for {
+			sseEvent, err := decoder.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				errorChan <- fmt.Errorf("error reading stream: %w", err)
+				return
+			}
+			if sseEvent.Data == "[DONE]" {
+				continue
+			}
+
+			// Event names are repeated in the JSON "type" field, so only the data matters
+			var event responsesStreamEvent
+			if err := json.Unmarshal([]byte(sseEvent.Data), &event); err != nil {
+				errorChan <- fmt.Errorf("failed to parse event: %w", err)
+				return
+			}
+
+			switch event.Type {
+			case "response.output_text.delta":
+				if event.Delta != "" && !send(&pb.LLMStreamResponse{
+					Type:    pb.ResponseType_TYPE_CONTENT,
+					Content: event.Delta,
+				}) {
+					return
+				}
+			case "response.reasoning_summary_text.delta":
+				if event.Delta != "" && !send(&pb.LLMStreamResponse{
+					Type:    pb.ResponseType_TYPE_REASONING,
+					Content: event.Delta,
+				}) {
+					return
+				}
+			case "response.completed", "response.incomplete":
+				if event.Response == nil {
+					errorChan <- fmt.Errorf("%s event without response", event.Type)
+					return
+				}
+				if !send(&pb.LLMStreamResponse{
+					Type:         pb.ResponseType_TYPE_FINISH_REASON,
+					FinishReason: responsesFinishReason(event.Response),
+					ResponseId:   event.Response.ID,
+				}) {
+					return
+				}
+				if event.Response.Usage != nil {
+					send(&pb.LLMStreamResponse{
+						Type:  pb.ResponseType_TYPE_USAGE,
+						Usage: convertResponsesUsage(event.Response.Usage),
+					})
+				}
+				return
+			case "response.failed":
+				if event.Response != nil && event.Response.Error != nil {
+					errorChan <- fmt.Errorf("response failed (%s): %s", event.Response.Error.Code, event.Response.Error.Message)
+				} else {
+					errorChan <- fmt.Errorf("response failed")
+				}
+				return
+			case "error":
+				errorChan <- fmt.Errorf("stream error (%s): %s", event.Code, event.Message)
+				return
+			}
+		}
+
+		errorChan <- fmt.Errorf("stream ended before response.completed: %w", io.ErrUnexpectedEOF)
+	}()
+
+	return responseChan, errorChan
+}
+
+// buildResponsesRequestBody converts an LLMRequest into the Responses API request format
+func (p *Provider) buildResponsesRequestBody(req *pb.LLMRequest, stream bool) responsesRequestBody {
+	model := req.Model
+	if model == "" {
+		model = p.config.DefaultModel
+	}
+
+	input := make([]chatMessage, len(req.Messages))
+	for i, msg := range req.Messages {
+		input[i] = chatMessage{
+			Role:    msg.Role,
+			Content: msg.Content,
+		}
+	}
+
+	body := responsesRequestBody{
+		Model:              model,
+		Input:              input,
+		PreviousResponseID: req.PreviousResponseId,
+		Stream:             stream,
+	}
+
+	if req.MaxTokens != 0 {
+		body.MaxOutputTokens = &req.MaxTokens
+	}
+	if isReasoningModel(model) {
+		body.Reasoning = &reasoningConfig{
+			Effort:  req.ReasoningEffort,
+			Summary: "auto",
+		}
+		return body
+	}
+	if req.Temperature != 0 {
+		body.Temperature = &req.Temperature
+	}
+	if req.TopP != 0 {
+		body.TopP = &req.TopP
+	}
+
+	return body
+}
+
+// postResponses sends a request to the /responses endpoint and returns the body on success
+func (p *Provider) postResponses(ctx context.Context, body responsesRequestBody) (io.ReadCloser, error) {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST",
+		fmt.Sprintf("%s/responses", p.config.BaseURL),
+		bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.config.APIKey))
+	client := p.httpClient
+	if body.Stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+		client = p.streamClient
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, provider.NewAPIError(resp, respBody)
+	}
+
+	return resp.Body, nil
+}
+
+// responsesFinishReason maps a response status to the Chat Completions finish reasons
+func responsesFinishReason(response *responsesResponseBody) string {
+	if response.Status != "incomplete" || response.IncompleteDetails == nil {
+		return "stop"
+	}
+	switch response.IncompleteDetails.Reason {
+	case "max_output_tokens":
+		return "length"
+	default:
+		return response.IncompleteDetails.Reason
+	}
+}
+
+// convertResponsesUsage converts Responses API usage to the proto format
+func convertResponsesUsage(usage *responsesUsage) *pb.UsageInfo {
+	if usage == nil {
+		return &pb.UsageInfo{}
+	}
+	return &pb.UsageInfo{
+		PromptTokens:     usage.InputTokens,
+		CompletionTokens: usage.OutputTokens,
+		TotalTokens:      usage.TotalTokens,
+	}
+}
+
+// isReasoningModel reports whether the model belongs to a reasoning family (o-series, gpt-5)
+func isReasoningModel(model string) bool {
+	for _, prefix := range []string{"o1", "o3", "o4", "gpt-5"} {
+		if strings.HasPrefix(model, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+func TestInvokeResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "POST", r.Method)
+		require.Equal(t, "/responses", r.URL.Path)
+		require.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+
+		var reqBody responsesRequestBody
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		require.Equal(t, "o4-mini", reqBody.Model)
+		require.Equal(t, "resp_prev", reqBody.PreviousResponseID)
+		require.Len(t, reqBody.Input, 1)
+		require.Equal(t, "And now?", reqBody.Input[0].Content)
+		require.Equal(t, int32(256), *reqBody.MaxOutputTokens)
+		require.NotNil(t, reqBody.Reasoning)
+		require.Equal(t, "auto", reqBody.Reasoning.Summary)
+
+		fmt.Fprint(w, `{
+			"id": "resp_123",
+			"object": "response",
+			"status": "completed",
+			"model": "o4-mini",
+			"output": [
+				{"type": "reasoning", "id": "rs_1", "summary": [{"type": "summary_text", "text": "Thinking about it."}]},
+				{"type": "message", "id": "msg_1", "role": "assistant", "content": [{"type": "output_text", "text": "Done.", "annotations": []}]}
+			],
+			"usage": {"input_tokens": 12, "output_tokens": 40, "total_tokens": 52, "output_tokens_details": {"reasoning_tokens": 32}}
+		}`)
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("test-key", "o4-mini").WithBaseURL(server.URL)).WithResponsesAPI()
+	resp, err := p.Invoke(context.Background(), &pb.LLMRequest{
+		Messages:           []*pb.ChatMessage{{Role: "user", Content: "And now?"}},
+		MaxTokens:          256,
+		PreviousResponseId: "resp_prev",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "resp_123", resp.Id)
+	require.Equal(t, "Done.", resp.Content)
+	require.Equal(t, "Thinking about it.", resp.Reasoning)
+	require.Equal(t, int32(12), resp.Usage.PromptTokens)
+	require.Equal(t, int32(40), resp.Usage.CompletionTokens)
+	require.Equal(t, int32(52), resp.Usage.TotalTokens)
+}
+
+func TestInvokeStreamResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody responsesRequestBody
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		require.True(t, reqBody.Stream)
+		require.Nil(t, reqBody.Reasoning)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		events := []struct{ name, data string }{
+			{"response.created", `{"type":"response.created","response":{"id":"resp_123","status":"in_progress","output":[]}}`},
+			{"response.output_item.added", `{"type":"response.output_item.added","output_index":0,"item":{"type":"message","id":"msg_1","role":"assistant","content":[]}}`},
+			{"response.output_text.delta", `{"type":"response.output_text.delta","item_id":"msg_1","output_index":0,"content_index":0,"delta":"Hello"}`},
+			{"response.output_text.delta", `{"type":"response.output_text.delta","item_id":"msg_1","output_index":0,"content_index":0,"delta":" world"}`},
+			{"response.output_text.done", `{"type":"response.output_text.done","item_id":"msg_1","output_index":0,"content_index":0,"text":"Hello world"}`},
+			{"response.incomplete", `{"type":"response.incomplete","response":{"id":"resp_123","status":"incomplete","incomplete_details":{"reason":"max_output_tokens"},"usage":{"input_tokens":5,"output_tokens":2,"total_tokens":7}}}`},
+		}
+		for _, event := range events {
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.name, event.data)
+		}
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("test-key", "gpt-4o").WithBaseURL(server.URL)).WithResponsesAPI()
+	respChan, errChan := p.InvokeStream(context.Background(), &pb.LLMRequest{
+		Messages: []*pb.ChatMessage{{Role: "user", Content: "Hi"}},
+	})
+
+	var responses []*pb.LLMStreamResponse
+	for resp := range respChan {
+		responses = append(responses, resp)
+	}
+	require.NoError(t, <-errChan)
+
+	require.Len(t, responses, 4)
+	require.Equal(t, "Hello", responses[0].Content)
+	require.Equal(t, " world", responses[1].Content)
+	require.Equal(t, pb.ResponseType_TYPE_FINISH_REASON, responses[2].Type)
+	require.Equal(t, "length", responses[2].FinishReason)
+	require.Equal(t, "resp_123", responses[2].ResponseId)
+	require.Equal(t, pb.ResponseType_TYPE_USAGE, responses[3].Type)
+	require.Equal(t, int32(7), responses[3].Usage.TotalTokens)
+}
+
+func TestInvokeStreamResponsesReasoningAndFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: response.reasoning_summary_text.delta\ndata: {\"type\":\"response.reasoning_summary_text.delta\",\"delta\":\"Considering\"}\n\n")
+		fmt.Fprint(w, "event: response.failed\ndata: {\"type\":\"response.failed\",\"response\":{\"id\":\"resp_1\",\"status\":\"failed\",\"error\":{\"code\":\"server_error\",\"message\":\"The model failed\"}}}\n\n")
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("test-key", "o3").WithBaseURL(server.URL)).WithResponsesAPI()
+	respChan, errChan := p.InvokeStream(context.Background(), &pb.LLMRequest{
+		Messages: []*pb.ChatMessage{{Role: "user", Content: "Hi"}},
+	})
+
+	resp := <-respChan
+	require.Equal(t, pb.ResponseType_TYPE_REASONING, resp.Type)
+	require.Equal(t, "Considering", resp.Content)
+
+	err := <-errChan
+	require.ErrorContains(t, err, "server_error")
+	_, ok := <-respChan
+	require.False(t, ok)
+}
+
+func TestPreviousResponseIDRequiresResponsesAPI(t *testing.T) {
+	p := New(provider.NewConfig("test-key", defaultModel))
+	req := &pb.LLMRequest{
+		Messages:           []*pb.ChatMessage{{Role: "user", Content: "Hi"}},
+		PreviousResponseId: "resp_prev",
+	}
+
+	_, err := p.Invoke(context.Background(), req)
+	require.ErrorIs(t, err, errPreviousResponseID)
+
+	respChan, errChan := p.InvokeStream(context.Background(), req)
+	require.ErrorIs(t, <-errChan, errPreviousResponseID)
+	_, ok := <-respChan
+	require.False(t, ok)
+}
@@ -0,0 +1,114 @@
+package openrouter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.uber.org/zap"
+
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+// Generation stats are recorded asynchronously, so a lookup right after a completion
+// can return 404 for a short while
+var (
+	generationLookupAttempts = 3
+	generationLookupDelay    = 500 * time.Millisecond
+)
+
+// generationResponse represents the JSON structure returned by /generation
+type generationResponse struct {
+	Data struct {
+		ID                     string  `json:"id"`
+		Model                  string  `json:"model"`
+		ProviderName           string  `json:"provider_name"`
+		TotalCost              float64 `json:"total_cost"`
+		NativeTokensPrompt     int32   `json:"native_tokens_prompt"`
+		NativeTokensCompletion int32   `json:"native_tokens_completion"`
+	} `json:"data"`
+}
+
+// WithGenerationLookup makes the provider fetch /generation after each completion to
+// report the upstream provider, native token counts and actual cost in the usage
+func (p *Provider) WithGenerationLookup() *Provider {
+	p.generationLookup = true
+	return p
+}
+
+// attachGeneration adds the generation stats to usage. A failed lookup is logged and
+// leaves usage unchanged, since the completion itself succeeded.
+func (p *Provider) attachGeneration(ctx context.Context, id string, usage *pb.UsageInfo) {
+	if !p.generationLookup || id == "" {
+		return
+	}
+
+	generation, err := p.fetchGeneration(ctx, id)
+	if err != nil {
+		zap.L().Warn("failed to fetch OpenRouter generation stats",
+			zap.String("generation_id", id),
+			zap.Error(err))
+		return
+	}
+
+	usage.CostUsd = generation.Data.TotalCost
+	usage.UpstreamProvider = generation.Data.ProviderName
+	usage.NativePromptTokens = generation.Data.NativeTokensPrompt
+	usage.NativeCompletionTokens = generation.Data.NativeTokensCompletion
+}
+
+// fetchGeneration retrieves the stats for a generation, retrying while they are not yet available
+func (p *Provider) fetchGeneration(ctx context.Context, id string) (*generationResponse, error) {
+	var lastErr error
+	for attempt := 0; attempt < generationLookupAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(generationLookupDelay):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		generation, retry, err := p.getGeneration(ctx, id)
+		if err == nil {
+			return generation, nil
+		}
+		if !retry {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// getGeneration performs a single /generation request and reports whether a failure is retryable
+func (p *Provider) getGeneration(ctx context.Context, id string) (*generationResponse, bool, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET",
+		fmt.Sprintf("%s/generation?id=%s", p.config.BaseURL, url.QueryEscape(id)), nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create request: %w", err)
+	}
+	p.setHeaders(httpReq)
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		retry := resp.StatusCode == http.StatusNotFound || resp.StatusCode >= http.StatusInternalServerError
+		return nil, retry, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, body)
+	}
+
+	var generation generationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&generation); err != nil {
+		return nil, false, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &generation, false, nil
+}
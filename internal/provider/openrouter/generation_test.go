@@ -0,0 +1,118 @@
+package openrouter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+const generationStats = `{"data":{"id":"gen-123","model":"anthropic/claude-3.5-sonnet","provider_name":"Amazon Bedrock","total_cost":0.00042,"native_tokens_prompt":11,"native_tokens_completion":7}}`
+
+func TestInvokeWithGenerationLookup(t *testing.T) {
+	generationLookupDelay = 0
+
+	var lookups atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/chat/completions":
+			fmt.Fprint(w, `{"id":"gen-123","model":"anthropic/claude-3.5-sonnet","choices":[{"message":{"role":"assistant","content":"Hi"},"finish_reason":"stop"}],"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`)
+		case "/generation":
+			require.Equal(t, "gen-123", r.URL.Query().Get("id"))
+			require.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+
+			// Stats are not available on the first lookup
+			if lookups.Add(1) == 1 {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"error":{"message":"Generation not found"}}`)
+				return
+			}
+			fmt.Fprint(w, generationStats)
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("test-key", "anthropic/claude-3.5-sonnet").WithBaseURL(server.URL)).
+		WithGenerationLookup()
+
+	resp, err := p.Invoke(context.Background(), &pb.LLMRequest{
+		Messages: []*pb.ChatMessage{{Role: "user", Content: "Hello"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "gen-123", resp.Id)
+	require.Equal(t, int32(15), resp.Usage.TotalTokens)
+	require.Equal(t, 0.00042, resp.Usage.CostUsd)
+	require.Equal(t, "Amazon Bedrock", resp.Usage.UpstreamProvider)
+	require.Equal(t, int32(11), resp.Usage.NativePromptTokens)
+	require.Equal(t, int32(7), resp.Usage.NativeCompletionTokens)
+	require.Equal(t, int32(2), lookups.Load())
+}
+
+func TestInvokeGenerationLookupFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/chat/completions":
+			fmt.Fprint(w, `{"id":"gen-123","choices":[{"message":{"role":"assistant","content":"Hi"},"finish_reason":"stop"}],"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`)
+		case "/generation":
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("test-key", "test-model").WithBaseURL(server.URL)).
+		WithGenerationLookup()
+
+	// The completion still succeeds, just without cost data
+	resp, err := p.Invoke(context.Background(), &pb.LLMRequest{
+		Messages: []*pb.ChatMessage{{Role: "user", Content: "Hello"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(15), resp.Usage.TotalTokens)
+	require.Zero(t, resp.Usage.CostUsd)
+	require.Empty(t, resp.Usage.UpstreamProvider)
+}
+
+func TestInvokeStreamWithGenerationLookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/chat/completions":
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"id\":\"gen-123\",\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\n\n")
+			fmt.Fprint(w, "data: {\"id\":\"gen-123\",\"choices\":[{\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n")
+			fmt.Fprint(w, "data: {\"id\":\"gen-123\",\"choices\":[],\"usage\":{\"prompt_tokens\":10,\"completion_tokens\":5,\"total_tokens\":15}}\n\n")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		case "/generation":
+			fmt.Fprint(w, generationStats)
+		}
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("test-key", "test-model").WithBaseURL(server.URL)).
+		WithGenerationLookup()
+
+	respChan, errChan := p.InvokeStream(context.Background(), &pb.LLMRequest{
+		Messages: []*pb.ChatMessage{{Role: "user", Content: "Hello"}},
+	})
+
+	var usage *pb.UsageInfo
+	for resp := range respChan {
+		if resp.Type == pb.ResponseType_TYPE_USAGE {
+			usage = resp.Usage
+		}
+	}
+	require.NoError(t, <-errChan)
+
+	require.NotNil(t, usage)
+	require.Equal(t, int32(15), usage.TotalTokens)
+	require.Equal(t, 0.00042, usage.CostUsd)
+	require.Equal(t, "Amazon Bedrock", usage.UpstreamProvider)
+}
@@ -1,20 +1,17 @@
 package openrouter
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
-	"strings"
-	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/c0rtexR/llm_service/internal/provider"
+	"github.com/c0rtexR/llm_service/internal/sse"
 	pb "github.com/c0rtexR/llm_service/proto"
 )
 
@@ -25,35 +22,33 @@ const (
 
 // Provider implements the LLMProvider interface for OpenRouter
 type Provider struct {
-	config     *provider.Config
-	httpClient *http.Client
-}
-
-var defaultTransport = &http.Transport{
-	Proxy: http.ProxyFromEnvironment,
-	DialContext: (&net.Dialer{
-		Timeout:   30 * time.Second,
-		KeepAlive: 30 * time.Second,
-	}).DialContext,
-	ForceAttemptHTTP2:     true,
-	MaxIdleConns:          100,
-	IdleConnTimeout:       90 * time.Second,
-	TLSHandshakeTimeout:   10 * time.Second,
-	ExpectContinueTimeout: 1 * time.Second,
-	MaxIdleConnsPerHost:   10,
-	ReadBufferSize:        64 * 1024,
-	WriteBufferSize:       64 * 1024,
-	DisableCompression:    true,
+	config       *provider.Config
+	httpClient   *http.Client
+	streamClient *http.Client
+	routing      Routing
+	referer      string
+	title        string
+	// generationLookup fetches actual cost and upstream provider after each completion
+	generationLookup bool
 }
 
 // requestBody represents the JSON structure for OpenRouter API requests
 type requestBody struct {
-	Model       string        `json:"model"`
-	Messages    []chatMessage `json:"messages"`
-	Stream      bool          `json:"stream,omitempty"`
-	Temperature *float32      `json:"temperature,omitempty"`
-	MaxTokens   *int32        `json:"max_tokens,omitempty"`
-	TopP        *float32      `json:"top_p,omitempty"`
+	Model       string               `json:"model"`
+	Models      []string             `json:"models,omitempty"`
+	Messages    []chatMessage        `json:"messages"`
+	Stream      bool                 `json:"stream,omitempty"`
+	Temperature *float32             `json:"temperature,omitempty"`
+	MaxTokens   *int32               `json:"max_tokens,omitempty"`
+	TopP        *float32             `json:"top_p,omitempty"`
+	Provider    *providerPreferences `json:"provider,omitempty"`
+	Transforms  []string             `json:"transforms,omitempty"`
+	Usage       *usageOptions        `json:"usage,omitempty"`
+}
+
+// usageOptions enables usage accounting; with include set, streams end with a usage chunk
+type usageOptions struct {
+	Include bool `json:"include"`
 }
 
 // chatMessage represents a single message in the OpenRouter format
@@ -100,7 +95,7 @@ type streamResponseBody struct {
 
 // streamProcessor handles the SSE stream processing
 type streamProcessor struct {
-	reader       *bufio.Reader
+	decoder      *sse.Decoder
 	responseChan chan<- *pb.LLMStreamResponse
 	errorChan    chan<- error
 	ctx          context.Context
@@ -108,15 +103,17 @@ type streamProcessor struct {
 
 func newStreamProcessor(ctx context.Context, body io.Reader, responseChan chan<- *pb.LLMStreamResponse, errorChan chan<- error) *streamProcessor {
 	return &streamProcessor{
-		reader:       bufio.NewReaderSize(body, 64*1024),
+		decoder:      sse.NewDecoder(body),
 		responseChan: responseChan,
 		errorChan:    errorChan,
 		ctx:          ctx,
 	}
 }
 
-func (sp *streamProcessor) process() {
-	var usage *pb.UsageInfo
+// process forwards content and finish reasons, and returns the generation ID and usage
+// once the stream completes. ok is false if the stream failed, ended before [DONE] or
+// was canceled.
+func (sp *streamProcessor) process() (id string, usage *pb.UsageInfo, ok bool) {
 	dataChan := make(chan string, 100)
 	errChan := make(chan error, 1)
 
@@ -130,7 +127,7 @@ func (sp *streamProcessor) process() {
 			case <-sp.ctx.Done():
 				return
 			default:
-				line, err := sp.reader.ReadString('\n')
+				event, err := sp.decoder.Next()
 				if err != nil {
 					if err != io.EOF {
 						errChan <- fmt.Errorf("error reading stream: %w", err)
@@ -139,7 +136,7 @@ func (sp *streamProcessor) process() {
 				}
 
 				select {
-				case dataChan <- line:
+				case dataChan <- event.Data:
 				case <-sp.ctx.Done():
 					return
 				}
@@ -151,47 +148,46 @@ func (sp *streamProcessor) process() {
 	for {
 		select {
 		case <-sp.ctx.Done():
-			return
+			return id, usage, false
 		case err := <-errChan:
-			if err != nil {
-				sp.errorChan <- err
-			}
-			return
-		case line, ok := <-dataChan:
-			if !ok {
-				return
-			}
-
-			line = strings.TrimSpace(line)
-			if line == "" {
+			if err == nil {
+				// The reader closed at EOF; keep draining buffered events
+				errChan = nil
 				continue
 			}
-
-			if !strings.HasPrefix(line, "data: ") {
-				continue
+			sp.errorChan <- err
+			return id, usage, false
+		case data, more := <-dataChan:
+			if !more {
+				// The reader closes errChan before dataChan, so a read error that lost
+				// the race with the close is still there
+				var err error
+				if errChan != nil {
+					err = <-errChan
+				}
+				if err != nil {
+					sp.errorChan <- err
+				} else if sp.ctx.Err() == nil {
+					sp.errorChan <- fmt.Errorf("stream ended before [DONE]: %w", io.ErrUnexpectedEOF)
+				}
+				return id, usage, false
 			}
 
-			data := strings.TrimPrefix(line, "data: ")
 			if data == "[DONE]" {
-				if usage != nil {
-					sp.sendResponse(&pb.LLMStreamResponse{
-						Type:  pb.ResponseType_TYPE_USAGE,
-						Usage: usage,
-					})
-				}
-				return
+				return id, usage, true
 			}
 
 			var streamResp streamResponseBody
 			if err := json.Unmarshal([]byte(data), &streamResp); err != nil {
 				sp.errorChan <- fmt.Errorf("failed to parse SSE data: %w", err)
-				return
+				return id, usage, false
 			}
 
-			if len(streamResp.Choices) == 0 {
-				continue
+			if streamResp.ID != "" {
+				id = streamResp.ID
 			}
 
+			// The usage chunk may arrive with no choices
 			if streamResp.Usage != nil {
 				usage = &pb.UsageInfo{
 					PromptTokens:     streamResp.Usage.PromptTokens,
@@ -200,6 +196,10 @@ func (sp *streamProcessor) process() {
 				}
 			}
 
+			if len(streamResp.Choices) == 0 {
+				continue
+			}
+
 			chunk := streamResp.Choices[0].Delta.Content
 			if streamResp.Choices[0].FinishReason != "" {
 				sp.sendResponse(&pb.LLMStreamResponse{
@@ -242,11 +242,11 @@ func New(config *provider.Config) *Provider {
 		zap.String("api_key_length", fmt.Sprintf("%d", len(config.APIKey))))
 
 	return &Provider{
-		config: config,
-		httpClient: &http.Client{
-			Transport: defaultTransport,
-			Timeout:   30 * time.Second,
-		},
+		config:       config,
+		httpClient:   config.NewHTTPClient(),
+		streamClient: config.NewStreamingHTTPClient(),
+		referer:      defaultReferer,
+		title:        defaultTitle,
 	}
 }
 
@@ -265,30 +265,9 @@ func (p *Provider) Invoke(ctx context.Context, req *pb.LLMRequest) (*pb.LLMRespo
 		zap.Int("messages_count", len(req.Messages)),
 		zap.String("api_key_length", fmt.Sprintf("%d", len(p.config.APIKey))))
 
-	// Convert messages to OpenRouter format
-	messages := make([]chatMessage, len(req.Messages))
-	for i, msg := range req.Messages {
-		messages[i] = chatMessage{
-			Role:    msg.Role,
-			Content: msg.Content,
-		}
-	}
-
-	// Prepare request body
-	body := requestBody{
-		Model:    model,
-		Messages: messages,
-	}
-
-	// Add optional parameters if provided
-	if req.Temperature != 0 {
-		body.Temperature = &req.Temperature
-	}
-	if req.MaxTokens != 0 {
-		body.MaxTokens = &req.MaxTokens
-	}
-	if req.TopP != 0 {
-		body.TopP = &req.TopP
+	body, err := p.buildRequestBody(req, model)
+	if err != nil {
+		return nil, err
 	}
 
 	// Marshal request body
@@ -308,12 +287,8 @@ func (p *Provider) Invoke(ctx context.Context, req *pb.LLMRequest) (*pb.LLMRespo
 	}
 
 	// Set headers
-	httpReq.Header.Set("Content-Type", "application/json")
-	authHeader := fmt.Sprintf("Bearer %s", p.config.APIKey)
-	httpReq.Header.Set("Authorization", authHeader)
-	httpReq.Header.Set("HTTP-Referer", "https://github.com/your-username/llm-service")
-	httpReq.Header.Set("X-Title", "LLM Service - Cursor IDE")
-	httpReq.Header.Set("User-Agent", "github.com/c0rtexR/llm_service/1.0.0")
+	p.setHeaders(httpReq)
+	authHeader := httpReq.Header.Get("Authorization")
 
 	logger.Info("sending request to OpenRouter",
 		zap.String("url", httpReq.URL.String()),
@@ -344,7 +319,7 @@ func (p *Provider) Invoke(ctx context.Context, req *pb.LLMRequest) (*pb.LLMRespo
 
 	// Check for error response
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, respBody)
+		return nil, provider.NewAPIError(resp, respBody)
 	}
 
 	// Parse response
@@ -362,14 +337,18 @@ func (p *Provider) Invoke(ctx context.Context, req *pb.LLMRequest) (*pb.LLMRespo
 		zap.String("model", response.Model),
 		zap.Int32("total_tokens", response.Usage.TotalTokens))
 
+	usage := &pb.UsageInfo{
+		PromptTokens:     response.Usage.PromptTokens,
+		CompletionTokens: response.Usage.CompletionTokens,
+		TotalTokens:      response.Usage.TotalTokens,
+	}
+	p.attachGeneration(ctx, response.ID, usage)
+
 	// Convert to proto response
 	return &pb.LLMResponse{
+		Id:      response.ID,
 		Content: response.Choices[0].Message.Content,
-		Usage: &pb.UsageInfo{
-			PromptTokens:     response.Usage.PromptTokens,
-			CompletionTokens: response.Usage.CompletionTokens,
-			TotalTokens:      response.Usage.TotalTokens,
-		},
+		Usage:   usage,
 	}, nil
 }
 
@@ -382,42 +361,19 @@ func (p *Provider) InvokeStream(ctx context.Context, req *pb.LLMRequest) (<-chan
 		defer close(responseChan)
 		defer close(errorChan)
 
-		// Create a context with timeout
-		ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
-		defer cancel()
-
 		// Use model from request or fall back to default
 		model := req.Model
 		if model == "" {
 			model = p.config.DefaultModel
 		}
 
-		// Pre-allocate messages slice with capacity
-		messages := make([]chatMessage, 0, len(req.Messages))
-		for _, msg := range req.Messages {
-			messages = append(messages, chatMessage{
-				Role:    msg.Role,
-				Content: msg.Content,
-			})
-		}
-
-		// Prepare request body
-		body := requestBody{
-			Model:    model,
-			Messages: messages,
-			Stream:   true,
-		}
-
-		// Add optional parameters if provided
-		if req.Temperature != 0 {
-			body.Temperature = &req.Temperature
-		}
-		if req.MaxTokens != 0 {
-			body.MaxTokens = &req.MaxTokens
-		}
-		if req.TopP != 0 {
-			body.TopP = &req.TopP
+		body, err := p.buildRequestBody(req, model)
+		if err != nil {
+			errorChan <- err
+			return
 		}
+		body.Stream = true
+		body.Usage = &usageOptions{Include: true}
 
 		// Marshal request body
 		jsonBody, err := json.Marshal(body)
@@ -436,22 +392,14 @@ func (p *Provider) InvokeStream(ctx context.Context, req *pb.LLMRequest) (<-chan
 		}
 
 		// Set headers
-		httpReq.Header.Set("Content-Type", "application/json")
-		httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.config.APIKey))
+		p.setHeaders(httpReq)
 		httpReq.Header.Set("Accept", "text/event-stream")
-		httpReq.Header.Set("HTTP-Referer", "https://github.com/your-username/llm-service")
-		httpReq.Header.Set("X-Title", "LLM Service - Cursor IDE")
-		httpReq.Header.Set("User-Agent", "github.com/c0rtexR/llm_service/1.0.0")
 		httpReq.Header.Set("Connection", "keep-alive")
 		httpReq.Header.Set("Cache-Control", "no-cache")
 		httpReq.Header.Set("Transfer-Encoding", "chunked")
 
-		// Send request with timeout
-		client := &http.Client{
-			Transport: defaultTransport,
-			Timeout:   60 * time.Second,
-		}
-		resp, err := client.Do(httpReq)
+		// Send request
+		resp, err := p.streamClient.Do(httpReq)
 		if err != nil {
 			errorChan <- fmt.Errorf("failed to send request: %w", err)
 			return
@@ -461,14 +409,81 @@ func (p *Provider) InvokeStream(ctx context.Context, req *pb.LLMRequest) (<-chan
 		// Check for error response
 		if resp.StatusCode != http.StatusOK {
 			body, _ := io.ReadAll(resp.Body)
-			errorChan <- fmt.Errorf("request failed with status %d: %s", resp.StatusCode, body)
+			errorChan <- provider.NewAPIError(resp, body)
 			return
 		}
 
 		// Create stream processor
 		processor := newStreamProcessor(ctx, resp.Body, responseChan, errorChan)
-		processor.process()
+		id, usage, ok := processor.process()
+		if !ok {
+			return
+		}
+
+		if p.generationLookup && id != "" {
+			if usage == nil {
+				usage = &pb.UsageInfo{}
+			}
+			p.attachGeneration(ctx, id, usage)
+		}
+		if usage != nil {
+			processor.sendResponse(&pb.LLMStreamResponse{
+				Type:  pb.ResponseType_TYPE_USAGE,
+				Usage: usage,
+			})
+		}
 	}()
 
 	return responseChan, errorChan
 }
+
+// buildRequestBody converts an LLMRequest into the OpenRouter request format
+func (p *Provider) buildRequestBody(req *pb.LLMRequest, model string) (requestBody, error) {
+	routing, err := p.mergeRouting(req.Openrouter)
+	if err != nil {
+		return requestBody{}, err
+	}
+
+	// Convert messages to OpenRouter format
+	messages := make([]chatMessage, len(req.Messages))
+	for i, msg := range req.Messages {
+		messages[i] = chatMessage{
+			Role:    msg.Role,
+			Content: msg.Content,
+		}
+	}
+
+	body := requestBody{
+		Model:      model,
+		Models:     routing.Models,
+		Messages:   messages,
+		Provider:   routing.preferences(),
+		Transforms: routing.Transforms,
+	}
+
+	// Add optional parameters if provided
+	if req.Temperature != 0 {
+		body.Temperature = &req.Temperature
+	}
+	if req.MaxTokens != 0 {
+		body.MaxTokens = &req.MaxTokens
+	}
+	if req.TopP != 0 {
+		body.TopP = &req.TopP
+	}
+
+	return body, nil
+}
+
+// setHeaders sets the authentication and app attribution headers
+func (p *Provider) setHeaders(httpReq *http.Request) {
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.config.APIKey))
+	if p.referer != "" {
+		httpReq.Header.Set("HTTP-Referer", p.referer)
+	}
+	if p.title != "" {
+		httpReq.Header.Set("X-Title", p.title)
+	}
+	httpReq.Header.Set("User-Agent", "github.com/c0rtexR/llm_service/1.0.0")
+}
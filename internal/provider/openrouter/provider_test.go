@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -285,6 +287,67 @@ func TestInvokeStream(t *testing.T) {
 	require.Equal(t, int32(15), lastUsage.TotalTokens)
 }
 
+func TestInvokeStreamFraming(t *testing.T) {
+	// Larger than bufio.Scanner's default 64KB token limit
+	large := strings.Repeat("x", 100*1024)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+
+		// Keep-alive comments, CRLF line endings, "data:" without a space and a
+		// payload split across data lines are all valid SSE
+		fmt.Fprint(w, ": OPENROUTER PROCESSING\r\n\r\n")
+		fmt.Fprint(w, "data:{\"id\":\"gen-1\",\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\r\n\r\n")
+		fmt.Fprint(w, "data: {\"id\":\"gen-1\",\ndata: \"choices\":[{\"delta\":{\"content\":\""+large+"\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"id\":\"gen-1\",\"choices\":[{\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("test-key", "test-model").WithBaseURL(server.URL))
+	respChan, errChan := p.InvokeStream(context.Background(), &pb.LLMRequest{
+		Messages: []*pb.ChatMessage{{Role: "user", Content: "Hello"}},
+	})
+
+	var content strings.Builder
+	var finishReason string
+	for resp := range respChan {
+		switch resp.Type {
+		case pb.ResponseType_TYPE_CONTENT:
+			content.WriteString(resp.Content)
+		case pb.ResponseType_TYPE_FINISH_REASON:
+			finishReason = resp.FinishReason
+		}
+	}
+	require.NoError(t, <-errChan)
+
+	require.Equal(t, "Hi"+large, content.String())
+	require.Equal(t, "stop", finishReason)
+}
+
+func TestInvokeStreamTruncated(t *testing.T) {
+	// The connection closes mid-answer, before the usage chunk and [DONE]
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"id\":\"gen-1\",\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\n\n")
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("test-key", "test-model").WithBaseURL(server.URL))
+	respChan, errChan := p.InvokeStream(context.Background(), &pb.LLMRequest{
+		Messages: []*pb.ChatMessage{{Role: "user", Content: "Hello"}},
+	})
+
+	var types []pb.ResponseType
+	for resp := range respChan {
+		types = append(types, resp.Type)
+	}
+	require.Equal(t, []pb.ResponseType{pb.ResponseType_TYPE_CONTENT}, types)
+	err := <-errChan
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.True(t, provider.IsRetryable(err))
+}
+
 func TestInvokeStreamErrors(t *testing.T) {
 	// Create a test server that returns an error
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,109 @@
+package openrouter
+
+import (
+	"fmt"
+
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+const (
+	defaultReferer = "https://github.com/c0rtexR/llm_service"
+	defaultTitle   = "LLM Service"
+)
+
+// Routing holds OpenRouter routing preferences. Set on the provider they act as
+// server-wide defaults; fields set on a request override them, except that a
+// "deny" data collection policy cannot be relaxed by a request.
+type Routing struct {
+	Order             []string
+	AllowFallbacks    *bool
+	DataCollection    string
+	RequireParameters bool
+	Quantizations     []string
+	Models            []string
+	Transforms        []string
+}
+
+// providerPreferences represents the "provider" object in OpenRouter requests
+type providerPreferences struct {
+	Order             []string `json:"order,omitempty"`
+	AllowFallbacks    *bool    `json:"allow_fallbacks,omitempty"`
+	DataCollection    string   `json:"data_collection,omitempty"`
+	RequireParameters bool     `json:"require_parameters,omitempty"`
+	Quantizations     []string `json:"quantizations,omitempty"`
+}
+
+// WithRouting sets the default routing preferences for all requests
+func (p *Provider) WithRouting(routing Routing) *Provider {
+	p.routing = routing
+	return p
+}
+
+// WithReferer sets the HTTP-Referer header OpenRouter uses to attribute requests
+// to an app; an empty value omits the header
+func (p *Provider) WithReferer(referer string) *Provider {
+	p.referer = referer
+	return p
+}
+
+// WithTitle sets the X-Title header shown for the app on OpenRouter; an empty value
+// omits the header
+func (p *Provider) WithTitle(title string) *Provider {
+	p.title = title
+	return p
+}
+
+// mergeRouting combines the provider defaults with the request's options
+func (p *Provider) mergeRouting(opts *pb.OpenRouterOptions) (Routing, error) {
+	routing := p.routing
+	if opts == nil {
+		return routing, nil
+	}
+
+	if len(opts.ProviderOrder) > 0 {
+		routing.Order = opts.ProviderOrder
+	}
+	if opts.AllowFallbacks != nil {
+		routing.AllowFallbacks = opts.AllowFallbacks
+	}
+	if opts.RequireParameters {
+		routing.RequireParameters = true
+	}
+	if len(opts.Quantizations) > 0 {
+		routing.Quantizations = opts.Quantizations
+	}
+	if len(opts.Models) > 0 {
+		routing.Models = opts.Models
+	}
+	if len(opts.Transforms) > 0 {
+		routing.Transforms = opts.Transforms
+	}
+
+	switch opts.DataCollection {
+	case "":
+	case "allow", "deny":
+		// The operator's policy is pinned; a request may only tighten it
+		if routing.DataCollection != "deny" {
+			routing.DataCollection = opts.DataCollection
+		}
+	default:
+		return Routing{}, fmt.Errorf("invalid data_collection %q: must be \"allow\" or \"deny\"", opts.DataCollection)
+	}
+
+	return routing, nil
+}
+
+// preferences returns the "provider" object for the request, or nil if nothing is set
+func (r Routing) preferences() *providerPreferences {
+	if len(r.Order) == 0 && r.AllowFallbacks == nil && r.DataCollection == "" &&
+		!r.RequireParameters && len(r.Quantizations) == 0 {
+		return nil
+	}
+	return &providerPreferences{
+		Order:             r.Order,
+		AllowFallbacks:    r.AllowFallbacks,
+		DataCollection:    r.DataCollection,
+		RequireParameters: r.RequireParameters,
+		Quantizations:     r.Quantizations,
+	}
+}
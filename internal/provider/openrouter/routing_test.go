@@ -0,0 +1,79 @@
+package openrouter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+func TestInvokeWithRouting(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "https://example.com/app", r.Header.Get("HTTP-Referer"))
+		require.Empty(t, r.Header.Values("X-Title"))
+
+		var raw map[string]json.RawMessage
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&raw))
+
+		// Request values override defaults, but the operator's deny policy is kept
+		require.JSONEq(t, `{
+			"order": ["Together", "DeepInfra"],
+			"allow_fallbacks": false,
+			"data_collection": "deny",
+			"quantizations": ["fp8"]
+		}`, string(raw["provider"]))
+		require.JSONEq(t, `["meta-llama/llama-3.1-70b-instruct", "mistralai/mixtral-8x7b-instruct"]`, string(raw["models"]))
+		require.JSONEq(t, `["middle-out"]`, string(raw["transforms"]))
+
+		fmt.Fprint(w, `{"id":"gen-1","model":"meta-llama/llama-3.1-70b-instruct","choices":[{"message":{"role":"assistant","content":"Hi"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`)
+	}))
+	defer server.Close()
+
+	allowFallbacks := false
+	p := New(provider.NewConfig("test-key", "test-model").WithBaseURL(server.URL)).
+		WithRouting(Routing{
+			Order:          []string{"Anthropic"},
+			DataCollection: "deny",
+			Quantizations:  []string{"fp8"},
+			Transforms:     []string{"middle-out"},
+		}).
+		WithReferer("https://example.com/app").
+		WithTitle("")
+
+	resp, err := p.Invoke(context.Background(), &pb.LLMRequest{
+		Messages: []*pb.ChatMessage{{Role: "user", Content: "Hello"}},
+		Openrouter: &pb.OpenRouterOptions{
+			ProviderOrder:  []string{"Together", "DeepInfra"},
+			AllowFallbacks: &allowFallbacks,
+			DataCollection: "allow",
+			Models:         []string{"meta-llama/llama-3.1-70b-instruct", "mistralai/mixtral-8x7b-instruct"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Hi", resp.Content)
+}
+
+func TestMergeRouting(t *testing.T) {
+	p := New(provider.NewConfig("test-key", "test-model"))
+
+	// Without defaults or options nothing is sent
+	routing, err := p.mergeRouting(nil)
+	require.NoError(t, err)
+	require.Nil(t, routing.preferences())
+
+	// A request can tighten the data collection policy
+	p.WithRouting(Routing{DataCollection: "allow"})
+	routing, err = p.mergeRouting(&pb.OpenRouterOptions{DataCollection: "deny", RequireParameters: true})
+	require.NoError(t, err)
+	require.Equal(t, &providerPreferences{DataCollection: "deny", RequireParameters: true}, routing.preferences())
+
+	_, err = p.mergeRouting(&pb.OpenRouterOptions{DataCollection: "sometimes"})
+	require.ErrorContains(t, err, "invalid data_collection")
+}
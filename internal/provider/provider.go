@@ -15,6 +15,49 @@ type LLMProvider interface {
 	InvokeStream(ctx context.Context, req *pb.LLMRequest) (<-chan *pb.LLMStreamResponse, <-chan error)
 }
 
+// BatchProvider is implemented by providers that support asynchronous batch processing
+type BatchProvider interface {
+	// CreateBatch submits a set of requests for batch processing
+	CreateBatch(ctx context.Context, items []*pb.BatchRequestItem) (*pb.BatchJob, error)
+
+	// GetBatch returns the current status of a batch
+	GetBatch(ctx context.Context, batchID string) (*pb.BatchJob, error)
+
+	// BatchResults streams the results of an ended batch
+	BatchResults(ctx context.Context, batchID string) (<-chan *pb.BatchResult, <-chan error)
+}
+
+// Wrapper is implemented by decorators that add behavior around another provider
+type Wrapper interface {
+	// Unwrap returns the wrapped provider
+	Unwrap() LLMProvider
+}
+
+// AsBatchProvider returns the first provider in a chain of wrappers that supports batches
+func AsBatchProvider(p LLMProvider) (BatchProvider, bool) {
+	for {
+		if bp, ok := p.(BatchProvider); ok {
+			return bp, true
+		}
+		w, ok := p.(Wrapper)
+		if !ok {
+			return nil, false
+		}
+		p = w.Unwrap()
+	}
+}
+
+// Unwrap returns the provider at the end of a chain of wrappers
+func Unwrap(p LLMProvider) LLMProvider {
+	for {
+		w, ok := p.(Wrapper)
+		if !ok {
+			return p
+		}
+		p = w.Unwrap()
+	}
+}
+
 // Config holds common configuration for LLM providers
 type Config struct {
 	// APIKey is the authentication key for the provider
@@ -25,6 +68,9 @@ type Config struct {
 
 	// BaseURL is the base URL for API requests (optional, for testing)
 	BaseURL string
+
+	// Transport holds HTTP timeouts, proxy, TLS and connection pool settings
+	Transport TransportConfig
 }
 
 // NewConfig creates a new provider configuration
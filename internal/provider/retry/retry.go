@@ -0,0 +1,190 @@
+// Package retry provides an LLMProvider decorator that retries transient failures
+// with jittered exponential backoff.
+package retry
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+// Policy controls how failed requests are retried
+type Policy struct {
+	// MaxAttempts is the total number of attempts, including the first; 1 disables retries
+	MaxAttempts int
+
+	// InitialBackoff is the base wait before the first retry
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the computed exponential backoff
+	MaxBackoff time.Duration
+
+	// Multiplier grows the backoff after each attempt
+	Multiplier float64
+
+	// MaxRetryAfter caps how long a provider's Retry-After hint is honored; a longer
+	// hint fails the request immediately rather than holding it open
+	MaxRetryAfter time.Duration
+}
+
+// DefaultPolicy returns the retry policy used when none is configured
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     20 * time.Second,
+		Multiplier:     2,
+		MaxRetryAfter:  time.Minute,
+	}
+}
+
+// Provider wraps an LLMProvider and retries requests that fail with a retryable error
+// (see provider.IsRetryable)
+type Provider struct {
+	next   provider.LLMProvider
+	name   string
+	policy Policy
+
+	// jitter returns a random fraction in [0, 1); replaced in tests
+	jitter func() float64
+}
+
+// New wraps next with the given retry policy. name identifies the provider in logs.
+func New(name string, next provider.LLMProvider, policy Policy) *Provider {
+	return &Provider{
+		next:   next,
+		name:   name,
+		policy: policy,
+		jitter: rand.Float64,
+	}
+}
+
+// Unwrap returns the wrapped provider
+func (p *Provider) Unwrap() provider.LLMProvider {
+	return p.next
+}
+
+// Invoke implements the LLMProvider interface
+func (p *Provider) Invoke(ctx context.Context, req *pb.LLMRequest) (*pb.LLMResponse, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := p.next.Invoke(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		if !p.shouldRetry(ctx, attempt, err) {
+			return nil, err
+		}
+	}
+}
+
+// InvokeStream implements the LLMProvider interface. A stream is only retried if it
+// fails before any chunk was delivered; after that the caller has partial output and
+// the error is passed through.
+func (p *Provider) InvokeStream(ctx context.Context, req *pb.LLMRequest) (<-chan *pb.LLMStreamResponse, <-chan error) {
+	responseChan := make(chan *pb.LLMStreamResponse)
+	errorChan := make(chan error, 1)
+
+	go func() {
+		defer close(responseChan)
+		defer close(errorChan)
+
+		for attempt := 1; ; attempt++ {
+			delivered, err := p.forward(ctx, req, responseChan)
+			if err == nil {
+				return
+			}
+			if delivered || !p.shouldRetry(ctx, attempt, err) {
+				errorChan <- err
+				return
+			}
+		}
+	}()
+
+	return responseChan, errorChan
+}
+
+// forward runs one streaming attempt, relaying its chunks. It reports whether any
+// chunk reached the caller and the error the attempt ended with.
+func (p *Provider) forward(ctx context.Context, req *pb.LLMRequest, out chan<- *pb.LLMStreamResponse) (delivered bool, err error) {
+	respChan, errChan := p.next.InvokeStream(ctx, req)
+
+	for respChan != nil || errChan != nil {
+		select {
+		case resp, ok := <-respChan:
+			if !ok {
+				respChan = nil
+				continue
+			}
+			select {
+			case out <- resp:
+				delivered = true
+			case <-ctx.Done():
+				return delivered, ctx.Err()
+			}
+		case err, ok := <-errChan:
+			if !ok {
+				errChan = nil
+				continue
+			}
+			if err != nil {
+				return delivered, err
+			}
+		}
+	}
+	return delivered, nil
+}
+
+// shouldRetry decides whether to retry after a failed attempt and, if so, waits out
+// the backoff. It returns false if the error is permanent, attempts are exhausted, or
+// the wait would not finish before the caller's deadline.
+func (p *Provider) shouldRetry(ctx context.Context, attempt int, err error) bool {
+	if attempt >= p.policy.MaxAttempts || !provider.IsRetryable(err) {
+		return false
+	}
+
+	wait := p.backoff(attempt)
+	if hint := provider.RetryAfterHint(err); hint > 0 {
+		if p.policy.MaxRetryAfter > 0 && hint > p.policy.MaxRetryAfter {
+			return false
+		}
+		wait = hint
+	}
+
+	// Waiting is pointless if the retry could not complete in time
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= wait {
+		return false
+	}
+
+	zap.L().Warn("retrying provider request",
+		zap.String("provider", p.name),
+		zap.Int("attempt", attempt),
+		zap.Duration("backoff", wait),
+		zap.Error(err))
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// backoff returns the jittered exponential backoff before the retry following attempt.
+// The wait is drawn from [d/2, d) so retries spread out but never collapse to zero.
+func (p *Provider) backoff(attempt int) time.Duration {
+	d := float64(p.policy.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		d *= p.policy.Multiplier
+	}
+	if p.policy.MaxBackoff > 0 {
+		d = min(d, float64(p.policy.MaxBackoff))
+	}
+	return time.Duration(d/2 + p.jitter()*d/2)
+}
@@ -0,0 +1,183 @@
+package retry
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+// fakeProvider fails the first failures calls with err, then succeeds
+type fakeProvider struct {
+	calls    atomic.Int32
+	failures int32
+	err      error
+
+	// chunks are streamed before a failing stream reports err
+	chunks []string
+}
+
+func (f *fakeProvider) Invoke(ctx context.Context, req *pb.LLMRequest) (*pb.LLMResponse, error) {
+	if f.calls.Add(1) <= f.failures {
+		return nil, f.err
+	}
+	return &pb.LLMResponse{Content: "ok"}, nil
+}
+
+func (f *fakeProvider) InvokeStream(ctx context.Context, req *pb.LLMRequest) (<-chan *pb.LLMStreamResponse, <-chan error) {
+	responseChan := make(chan *pb.LLMStreamResponse)
+	errorChan := make(chan error, 1)
+	failing := f.calls.Add(1) <= f.failures
+
+	go func() {
+		defer close(responseChan)
+		defer close(errorChan)
+
+		if failing {
+			for _, chunk := range f.chunks {
+				responseChan <- &pb.LLMStreamResponse{Type: pb.ResponseType_TYPE_CONTENT, Content: chunk}
+			}
+			errorChan <- f.err
+			return
+		}
+		responseChan <- &pb.LLMStreamResponse{Type: pb.ResponseType_TYPE_CONTENT, Content: "ok"}
+	}()
+
+	return responseChan, errorChan
+}
+
+// testPolicy retries quickly so tests stay fast
+func testPolicy() Policy {
+	return Policy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+		Multiplier:     2,
+		MaxRetryAfter:  time.Second,
+	}
+}
+
+var overloaded = &provider.APIError{StatusCode: provider.StatusOverloaded, Body: "overloaded"}
+
+// collect drains a stream into its content and final error
+func collect(respChan <-chan *pb.LLMStreamResponse, errChan <-chan error) ([]string, error) {
+	var content []string
+	for resp := range respChan {
+		content = append(content, resp.Content)
+	}
+	return content, <-errChan
+}
+
+func TestInvokeRetriesTransientErrors(t *testing.T) {
+	next := &fakeProvider{failures: 2, err: overloaded}
+	p := New("anthropic", next, testPolicy())
+
+	resp, err := p.Invoke(context.Background(), &pb.LLMRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp.Content)
+	require.Equal(t, int32(3), next.calls.Load())
+}
+
+func TestInvokeGivesUpAfterMaxAttempts(t *testing.T) {
+	next := &fakeProvider{failures: 5, err: overloaded}
+	p := New("anthropic", next, testPolicy())
+
+	_, err := p.Invoke(context.Background(), &pb.LLMRequest{})
+	require.ErrorIs(t, err, overloaded)
+	require.Equal(t, int32(3), next.calls.Load())
+}
+
+func TestInvokeDoesNotRetryPermanentErrors(t *testing.T) {
+	badRequest := &provider.APIError{StatusCode: http.StatusBadRequest}
+	next := &fakeProvider{failures: 1, err: badRequest}
+	p := New("openai", next, testPolicy())
+
+	_, err := p.Invoke(context.Background(), &pb.LLMRequest{})
+	require.ErrorIs(t, err, badRequest)
+	require.Equal(t, int32(1), next.calls.Load())
+}
+
+func TestInvokeHonorsRetryAfter(t *testing.T) {
+	rateLimited := &provider.APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: 50 * time.Millisecond}
+	next := &fakeProvider{failures: 1, err: rateLimited}
+	p := New("openai", next, testPolicy())
+
+	start := time.Now()
+	_, err := p.Invoke(context.Background(), &pb.LLMRequest{})
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	// A hint beyond MaxRetryAfter fails immediately
+	tooLong := &provider.APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Hour}
+	next = &fakeProvider{failures: 1, err: tooLong}
+	_, err = New("openai", next, testPolicy()).Invoke(context.Background(), &pb.LLMRequest{})
+	require.ErrorIs(t, err, tooLong)
+	require.Equal(t, int32(1), next.calls.Load())
+}
+
+func TestInvokeRespectsDeadline(t *testing.T) {
+	rateLimited := &provider.APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: 500 * time.Millisecond}
+	next := &fakeProvider{failures: 1, err: rateLimited}
+	p := New("openai", next, testPolicy())
+
+	// The retry could not finish before the deadline, so the error is returned at once
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := p.Invoke(ctx, &pb.LLMRequest{})
+	require.ErrorIs(t, err, rateLimited)
+	require.Less(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestInvokeStreamRetriesBeforeFirstChunk(t *testing.T) {
+	next := &fakeProvider{failures: 1, err: overloaded}
+	p := New("anthropic", next, testPolicy())
+
+	content, err := collect(p.InvokeStream(context.Background(), &pb.LLMRequest{}))
+	require.NoError(t, err)
+	require.Equal(t, []string{"ok"}, content)
+	require.Equal(t, int32(2), next.calls.Load())
+}
+
+func TestInvokeStreamDoesNotRetryAfterFirstChunk(t *testing.T) {
+	next := &fakeProvider{failures: 1, err: overloaded, chunks: []string{"partial"}}
+	p := New("anthropic", next, testPolicy())
+
+	content, err := collect(p.InvokeStream(context.Background(), &pb.LLMRequest{}))
+	require.ErrorIs(t, err, overloaded)
+	require.Equal(t, []string{"partial"}, content)
+	require.Equal(t, int32(1), next.calls.Load())
+}
+
+func TestBackoff(t *testing.T) {
+	p := New("openai", &fakeProvider{}, Policy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+	})
+
+	// Without jitter the wait is half the exponential step
+	p.jitter = func() float64 { return 0 }
+	require.Equal(t, 50*time.Millisecond, p.backoff(1))
+	require.Equal(t, 100*time.Millisecond, p.backoff(2))
+	require.Equal(t, 500*time.Millisecond, p.backoff(10))
+
+	p.jitter = func() float64 { return 0.999 }
+	require.InDelta(t, float64(400*time.Millisecond), float64(p.backoff(3)), float64(time.Millisecond))
+}
+
+func TestUnwrap(t *testing.T) {
+	next := &fakeProvider{}
+	p := New("openai", next, DefaultPolicy())
+	require.Same(t, next, p.Unwrap())
+
+	_, ok := provider.AsBatchProvider(p)
+	require.False(t, ok)
+}
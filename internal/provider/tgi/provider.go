@@ -0,0 +1,530 @@
+package tgi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	"github.com/c0rtexR/llm_service/internal/sse"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+const (
+	defaultBaseURL = "http://localhost:8080"
+	defaultModel   = "tgi"
+)
+
+// Provider implements the LLMProvider interface for Hugging Face Text Generation Inference.
+// By default it talks to the native /generate and /generate_stream endpoints; use
+// WithMessagesAPI to switch to the OpenAI-compatible /v1/chat/completions endpoint.
+type Provider struct {
+	config     *provider.Config
+	httpClient *http.Client
+	// streamClient is used for streaming requests, which take longer than RequestTimeout allows
+	streamClient *http.Client
+	messagesAPI  bool
+}
+
+// generateRequest represents the JSON structure for native TGI requests
+type generateRequest struct {
+	Inputs     string             `json:"inputs"`
+	Parameters generateParameters `json:"parameters"`
+	Stream     bool               `json:"stream,omitempty"`
+}
+
+// generateParameters holds the sampling parameters for native TGI requests
+type generateParameters struct {
+	MaxNewTokens      *int32   `json:"max_new_tokens,omitempty"`
+	Temperature       *float32 `json:"temperature,omitempty"`
+	TopP              *float32 `json:"top_p,omitempty"`
+	TopK              *int32   `json:"top_k,omitempty"`
+	RepetitionPenalty *float32 `json:"repetition_penalty,omitempty"`
+	Stop              []string `json:"stop,omitempty"`
+	DoSample          bool     `json:"do_sample,omitempty"`
+	Details           bool     `json:"details"`
+}
+
+// generateDetails holds the generation details returned by native TGI endpoints
+type generateDetails struct {
+	FinishReason    string `json:"finish_reason"`
+	GeneratedTokens int32  `json:"generated_tokens"`
+}
+
+// generateResponse represents the JSON structure for native TGI responses
+type generateResponse struct {
+	GeneratedText string           `json:"generated_text"`
+	Details       *generateDetails `json:"details,omitempty"`
+}
+
+// streamToken represents a single token event in the /generate_stream SSE stream
+type streamToken struct {
+	Token *struct {
+		ID      int32   `json:"id"`
+		Text    string  `json:"text"`
+		Logprob float32 `json:"logprob"`
+		Special bool    `json:"special"`
+	} `json:"token,omitempty"`
+	GeneratedText *string          `json:"generated_text,omitempty"`
+	Details       *generateDetails `json:"details,omitempty"`
+	Error         string           `json:"error,omitempty"`
+	ErrorType     string           `json:"error_type,omitempty"`
+}
+
+// chatRequest represents the JSON structure for Messages API requests
+type chatRequest struct {
+	Model         string         `json:"model"`
+	Messages      []chatMessage  `json:"messages"`
+	Stream        bool           `json:"stream,omitempty"`
+	StreamOptions *streamOptions `json:"stream_options,omitempty"`
+	Temperature   *float32       `json:"temperature,omitempty"`
+	MaxTokens     *int32         `json:"max_tokens,omitempty"`
+	TopP          *float32       `json:"top_p,omitempty"`
+	Stop          []string       `json:"stop,omitempty"`
+}
+
+// streamOptions requests a usage chunk at the end of a Messages API stream
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// chatMessage represents a single message in the Messages API format
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatUsage represents token usage reported by the Messages API
+type chatUsage struct {
+	PromptTokens     int32 `json:"prompt_tokens"`
+	CompletionTokens int32 `json:"completion_tokens"`
+	TotalTokens      int32 `json:"total_tokens"`
+}
+
+// chatResponse represents the JSON structure for Messages API responses
+type chatResponse struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Message struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage chatUsage `json:"usage"`
+}
+
+// chatStreamResponse represents a single chunk in the Messages API SSE stream
+type chatStreamResponse struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Delta struct {
+			Role    string `json:"role,omitempty"`
+			Content string `json:"content,omitempty"`
+		} `json:"delta"`
+		FinishReason *string `json:"finish_reason,omitempty"`
+	} `json:"choices"`
+	Usage *chatUsage `json:"usage,omitempty"`
+	Error string     `json:"error,omitempty"`
+}
+
+// New creates a new TGI provider instance
+func New(config *provider.Config) *Provider {
+	if config.BaseURL == "" {
+		config.BaseURL = defaultBaseURL
+	}
+	if config.DefaultModel == "" {
+		config.DefaultModel = defaultModel
+	}
+
+	return &Provider{
+		config:       config,
+		httpClient:   config.NewHTTPClient(),
+		streamClient: config.NewStreamingHTTPClient(),
+	}
+}
+
+// WithMessagesAPI switches the provider to TGI's OpenAI-compatible Messages API,
+// which applies the model's chat template server-side
+func (p *Provider) WithMessagesAPI() *Provider {
+	p.messagesAPI = true
+	return p
+}
+
+// Invoke implements the LLMProvider interface for synchronous requests
+func (p *Provider) Invoke(ctx context.Context, req *pb.LLMRequest) (*pb.LLMResponse, error) {
+	if p.messagesAPI {
+		return p.invokeChat(ctx, req)
+	}
+
+	body := p.buildGenerateRequest(req, false)
+	respBody, err := p.post(ctx, "/generate", body, false)
+	if err != nil {
+		return nil, err
+	}
+	defer respBody.Close()
+
+	data, err := io.ReadAll(respBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// TGI returns an object for a single input, but some deployments wrap it in an array
+	var response generateResponse
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var responses []generateResponse
+		if err := json.Unmarshal(trimmed, &responses); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+		if len(responses) == 0 {
+			return nil, fmt.Errorf("no generations in response")
+		}
+		response = responses[0]
+	} else if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &pb.LLMResponse{
+		Content: response.GeneratedText,
+		Usage:   generatedUsage(response.Details),
+	}, nil
+}
+
+// InvokeStream implements the LLMProvider interface for streaming requests
+func (p *Provider) InvokeStream(ctx context.Context, req *pb.LLMRequest) (<-chan *pb.LLMStreamResponse, <-chan error) {
+	responseChan := make(chan *pb.LLMStreamResponse)
+	errorChan := make(chan error, 1)
+
+	go func() {
+		defer close(responseChan)
+		defer close(errorChan)
+
+		var err error
+		if p.messagesAPI {
+			err = p.streamChat(ctx, req, responseChan)
+		} else {
+			err = p.streamGenerate(ctx, req, responseChan)
+		}
+		if err != nil {
+			errorChan <- err
+		}
+	}()
+
+	return responseChan, errorChan
+}
+
+// streamGenerate consumes the native /generate_stream token events
+func (p *Provider) streamGenerate(ctx context.Context, req *pb.LLMRequest, responseChan chan<- *pb.LLMStreamResponse) error {
+	body := p.buildGenerateRequest(req, true)
+	respBody, err := p.post(ctx, "/generate_stream", body, true)
+	if err != nil {
+		return err
+	}
+	defer respBody.Close()
+
+	decoder := sse.NewDecoder(respBody)
+
+	for {
+		sseEvent, err := decoder.Next()
+		if err == io.EOF {
+			// A complete stream ends with the generation details
+			return fmt.Errorf("stream ended before the generation details: %w", io.ErrUnexpectedEOF)
+		}
+		if err != nil {
+			return fmt.Errorf("error reading stream: %w", err)
+		}
+
+		var event streamToken
+		if err := json.Unmarshal([]byte(sseEvent.Data), &event); err != nil {
+			return fmt.Errorf("failed to parse SSE data: %w", err)
+		}
+		if event.Error != "" {
+			return fmt.Errorf("stream error (%s): %s", event.ErrorType, event.Error)
+		}
+
+		if event.Token != nil && !event.Token.Special && event.Token.Text != "" {
+			if !send(ctx, responseChan, &pb.LLMStreamResponse{
+				Type:    pb.ResponseType_TYPE_CONTENT,
+				Content: event.Token.Text,
+			}) {
+				return ctx.Err()
+			}
+		}
+
+		// The last event carries the generation details
+		if event.Details != nil {
+			if !send(ctx, responseChan, &pb.LLMStreamResponse{
+				Type:         pb.ResponseType_TYPE_FINISH_REASON,
+				FinishReason: finishReason(event.Details.FinishReason),
+			}) {
+				return ctx.Err()
+			}
+			if !send(ctx, responseChan, &pb.LLMStreamResponse{
+				Type:  pb.ResponseType_TYPE_USAGE,
+				Usage: generatedUsage(event.Details),
+			}) {
+				return ctx.Err()
+			}
+			return nil
+		}
+	}
+}
+
+// invokeChat sends a non-streaming Messages API request
+func (p *Provider) invokeChat(ctx context.Context, req *pb.LLMRequest) (*pb.LLMResponse, error) {
+	body := p.buildChatRequest(req, false)
+	respBody, err := p.post(ctx, "/v1/chat/completions", body, false)
+	if err != nil {
+		return nil, err
+	}
+	defer respBody.Close()
+
+	var response chatResponse
+	if err := json.NewDecoder(respBody).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("no completion choices in response")
+	}
+
+	return &pb.LLMResponse{
+		Content: response.Choices[0].Message.Content,
+		Usage: &pb.UsageInfo{
+			PromptTokens:     response.Usage.PromptTokens,
+			CompletionTokens: response.Usage.CompletionTokens,
+			TotalTokens:      response.Usage.TotalTokens,
+		},
+	}, nil
+}
+
+// streamChat consumes a Messages API SSE stream
+func (p *Provider) streamChat(ctx context.Context, req *pb.LLMRequest, responseChan chan<- *pb.LLMStreamResponse) error {
+	body := p.buildChatRequest(req, true)
+	respBody, err := p.post(ctx, "/v1/chat/completions", body, true)
+	if err != nil {
+		return err
+	}
+	defer respBody.Close()
+
+	decoder := sse.NewDecoder(respBody)
+	var usage *pb.UsageInfo
+
+	for {
+		event, err := decoder.Next()
+		if err == io.EOF {
+			return fmt.Errorf("stream ended before [DONE]: %w", io.ErrUnexpectedEOF)
+		}
+		if err != nil {
+			return fmt.Errorf("error reading stream: %w", err)
+		}
+		if event.Data == "[DONE]" {
+			break
+		}
+
+		var chunk chatStreamResponse
+		if err := json.Unmarshal([]byte(event.Data), &chunk); err != nil {
+			return fmt.Errorf("failed to parse chunk: %w", err)
+		}
+		if chunk.Error != "" {
+			return fmt.Errorf("stream error: %s", chunk.Error)
+		}
+
+		if chunk.Usage != nil {
+			usage = &pb.UsageInfo{
+				PromptTokens:     chunk.Usage.PromptTokens,
+				CompletionTokens: chunk.Usage.CompletionTokens,
+				TotalTokens:      chunk.Usage.TotalTokens,
+			}
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		if content := chunk.Choices[0].Delta.Content; content != "" {
+			if !send(ctx, responseChan, &pb.LLMStreamResponse{
+				Type:    pb.ResponseType_TYPE_CONTENT,
+				Content: content,
+			}) {
+				return ctx.Err()
+			}
+		}
+		if reason := chunk.Choices[0].FinishReason; reason != nil && *reason != "" {
+			if !send(ctx, responseChan, &pb.LLMStreamResponse{
+				Type:         pb.ResponseType_TYPE_FINISH_REASON,
+				FinishReason: finishReason(*reason),
+			}) {
+				return ctx.Err()
+			}
+		}
+	}
+
+	if usage != nil {
+		send(ctx, responseChan, &pb.LLMStreamResponse{
+			Type:  pb.ResponseType_TYPE_USAGE,
+			Usage: usage,
+		})
+	}
+	return nil
+}
+
+// buildGenerateRequest converts an LLMRequest into a native TGI request
+func (p *Provider) buildGenerateRequest(req *pb.LLMRequest, stream bool) generateRequest {
+	body := generateRequest{
+		Inputs: renderPrompt(req.Messages),
+		Stream: stream,
+		Parameters: generateParameters{
+			Stop:    req.Stop,
+			Details: true,
+		},
+	}
+
+	if req.Temperature != 0 {
+		body.Parameters.Temperature = &req.Temperature
+		body.Parameters.DoSample = true
+	}
+	if req.MaxTokens != 0 {
+		body.Parameters.MaxNewTokens = &req.MaxTokens
+	}
+	if req.TopP != 0 {
+		body.Parameters.TopP = &req.TopP
+		body.Parameters.DoSample = true
+	}
+	if req.TopK != 0 {
+		body.Parameters.TopK = &req.TopK
+		body.Parameters.DoSample = true
+	}
+	if req.RepetitionPenalty != 0 {
+		body.Parameters.RepetitionPenalty = &req.RepetitionPenalty
+	}
+
+	return body
+}
+
+// buildChatRequest converts an LLMRequest into a Messages API request.
+// The Messages API has no top_k or repetition_penalty, so those are not sent.
+func (p *Provider) buildChatRequest(req *pb.LLMRequest, stream bool) chatRequest {
+	model := req.Model
+	if model == "" {
+		model = p.config.DefaultModel
+	}
+
+	messages := make([]chatMessage, len(req.Messages))
+	for i, msg := range req.Messages {
+		messages[i] = chatMessage{
+			Role:    msg.Role,
+			Content: msg.Content,
+		}
+	}
+
+	body := chatRequest{
+		Model:    model,
+		Messages: messages,
+		Stream:   stream,
+		Stop:     req.Stop,
+	}
+	if stream {
+		body.StreamOptions = &streamOptions{IncludeUsage: true}
+	}
+
+	if req.Temperature != 0 {
+		body.Temperature = &req.Temperature
+	}
+	if req.MaxTokens != 0 {
+		body.MaxTokens = &req.MaxTokens
+	}
+	if req.TopP != 0 {
+		body.TopP = &req.TopP
+	}
+
+	return body
+}
+
+// post sends a JSON request to the given path and returns the response body on success
+func (p *Provider) post(ctx context.Context, path string, body interface{}, stream bool) (io.ReadCloser, error) {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST",
+		fmt.Sprintf("%s%s", strings.TrimSuffix(p.config.BaseURL, "/"), path),
+		bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.config.APIKey != "" {
+		httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.config.APIKey))
+	}
+	if stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+	}
+
+	client := p.httpClient
+	if stream {
+		client = p.streamClient
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, provider.NewAPIError(resp, respBody)
+	}
+
+	return resp.Body, nil
+}
+
+// renderPrompt flattens the conversation into a single prompt for the native API.
+// The native endpoints do not apply a chat template, so a single message is sent
+// verbatim and multi-turn conversations are joined with blank lines.
+func renderPrompt(messages []*pb.ChatMessage) string {
+	parts := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		parts = append(parts, msg.Content)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// generatedUsage maps TGI generation details to usage info.
+// TGI only reports generated tokens, so prompt tokens are left at zero.
+func generatedUsage(details *generateDetails) *pb.UsageInfo {
+	if details == nil {
+		return &pb.UsageInfo{}
+	}
+	return &pb.UsageInfo{
+		CompletionTokens: details.GeneratedTokens,
+		TotalTokens:      details.GeneratedTokens,
+	}
+}
+
+// finishReason maps TGI finish reasons to the OpenAI-style values used by the service
+func finishReason(reason string) string {
+	switch reason {
+	case "eos_token", "stop_sequence":
+		return "stop"
+	default:
+		return reason
+	}
+}
+
+// send delivers a stream response unless the context is cancelled first
+func send(ctx context.Context, responseChan chan<- *pb.LLMStreamResponse, resp *pb.LLMStreamResponse) bool {
+	select {
+	case responseChan <- resp:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,198 @@
+package tgi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+func TestNew(t *testing.T) {
+	p := New(provider.NewConfig("", ""))
+	require.NotNil(t, p)
+	require.Equal(t, defaultBaseURL, p.config.BaseURL)
+	require.Equal(t, defaultModel, p.config.DefaultModel)
+	require.False(t, p.messagesAPI)
+
+	p = New(provider.NewConfig("", "").WithBaseURL("http://tgi:80")).WithMessagesAPI()
+	require.Equal(t, "http://tgi:80", p.config.BaseURL)
+	require.True(t, p.messagesAPI)
+}
+
+func TestInvoke(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "POST", r.Method)
+		require.Equal(t, "/generate", r.URL.Path)
+		require.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+
+		var reqBody generateRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		require.Equal(t, "You are terse.\n\nHello", reqBody.Inputs)
+		require.False(t, reqBody.Stream)
+		require.True(t, reqBody.Parameters.Details)
+		require.True(t, reqBody.Parameters.DoSample)
+		require.Equal(t, int32(40), *reqBody.Parameters.TopK)
+		require.Equal(t, float32(1.2), *reqBody.Parameters.RepetitionPenalty)
+		require.Equal(t, int32(64), *reqBody.Parameters.MaxNewTokens)
+		require.Equal(t, []string{"</s>"}, reqBody.Parameters.Stop)
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"generated_text":"Hi there","details":{"finish_reason":"eos_token","generated_tokens":3,"seed":null}}`)
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("test-key", "").WithBaseURL(server.URL))
+	resp, err := p.Invoke(context.Background(), &pb.LLMRequest{
+		Messages: []*pb.ChatMessage{
+			{Role: "system", Content: "You are terse."},
+			{Role: "user", Content: "Hello"},
+		},
+		MaxTokens:         64,
+		TopK:              40,
+		RepetitionPenalty: 1.2,
+		Stop:              []string{"</s>"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Hi there", resp.Content)
+	require.Equal(t, int32(3), resp.Usage.CompletionTokens)
+	require.Equal(t, int32(3), resp.Usage.TotalTokens)
+}
+
+func TestInvokeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"error":"Input validation error","error_type":"validation"}`))
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("", "").WithBaseURL(server.URL))
+	resp, err := p.Invoke(context.Background(), &pb.LLMRequest{
+		Messages: []*pb.ChatMessage{{Role: "user", Content: "Hello"}},
+	})
+	require.Error(t, err)
+	require.Nil(t, resp)
+	require.Contains(t, err.Error(), "request failed with status 422")
+}
+
+func TestInvokeStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/generate_stream", r.URL.Path)
+		require.Equal(t, "text/event-stream", r.Header.Get("Accept"))
+		require.Empty(t, r.Header.Get("Authorization"))
+
+		var reqBody generateRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		require.True(t, reqBody.Stream)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+		events := []string{
+			`{"token":{"id":1,"text":"Hello","logprob":-0.1,"special":false},"generated_text":null,"details":null}`,
+			`{"token":{"id":2,"text":" world","logprob":-0.2,"special":false},"generated_text":null,"details":null}`,
+			`{"token":{"id":3,"text":"</s>","logprob":-0.3,"special":true},"generated_text":"Hello world","details":{"finish_reason":"eos_token","generated_tokens":3,"seed":null}}`,
+		}
+		for _, event := range events {
+			fmt.Fprintf(w, "data:%s\n\n", event)
+			flusher.Flush()
+		}
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("", "").WithBaseURL(server.URL))
+	respChan, errChan := p.InvokeStream(context.Background(), &pb.LLMRequest{
+		Messages: []*pb.ChatMessage{{Role: "user", Content: "Hello"}},
+	})
+
+	var responses []*pb.LLMStreamResponse
+	for resp := range respChan {
+		responses = append(responses, resp)
+	}
+	require.NoError(t, <-errChan)
+
+	require.Len(t, responses, 4)
+	require.Equal(t, "Hello", responses[0].Content)
+	require.Equal(t, " world", responses[1].Content)
+	require.Equal(t, pb.ResponseType_TYPE_FINISH_REASON, responses[2].Type)
+	require.Equal(t, "stop", responses[2].FinishReason)
+	require.Equal(t, pb.ResponseType_TYPE_USAGE, responses[3].Type)
+	require.Equal(t, int32(3), responses[3].Usage.CompletionTokens)
+}
+
+func TestInvokeStreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data:{\"error\":\"Request failed during generation: CUDA out of memory\",\"error_type\":\"generation\"}\n\n")
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("", "").WithBaseURL(server.URL))
+	respChan, errChan := p.InvokeStream(context.Background(), &pb.LLMRequest{
+		Messages: []*pb.ChatMessage{{Role: "user", Content: "Hello"}},
+	})
+
+	err := <-errChan
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "CUDA out of memory")
+
+	_, ok := <-respChan
+	require.False(t, ok)
+}
+
+func TestMessagesAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/chat/completions", r.URL.Path)
+
+		var reqBody chatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		require.Equal(t, defaultModel, reqBody.Model)
+		require.Len(t, reqBody.Messages, 2)
+		require.Equal(t, []string{"\n\n"}, reqBody.Stop)
+
+		if !reqBody.Stream {
+			fmt.Fprint(w, `{"id":"1","model":"tgi","choices":[{"message":{"role":"assistant","content":"Hi"},"finish_reason":"stop"}],"usage":{"prompt_tokens":12,"completion_tokens":2,"total_tokens":14}}`)
+			return
+		}
+
+		require.NotNil(t, reqBody.StreamOptions)
+		require.True(t, reqBody.StreamOptions.IncludeUsage)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data:{\"id\":\"1\",\"model\":\"tgi\",\"choices\":[{\"delta\":{\"role\":\"assistant\",\"content\":\"Hi\"},\"finish_reason\":null}]}\n\n")
+		fmt.Fprint(w, "data:{\"id\":\"1\",\"model\":\"tgi\",\"choices\":[{\"delta\":{\"content\":\"\"},\"finish_reason\":\"stop\"}],\"usage\":{\"prompt_tokens\":12,\"completion_tokens\":2,\"total_tokens\":14}}\n\n")
+		fmt.Fprint(w, "data:[DONE]\n\n")
+	}))
+	defer server.Close()
+
+	p := New(provider.NewConfig("", "").WithBaseURL(server.URL)).WithMessagesAPI()
+	req := &pb.LLMRequest{
+		Messages: []*pb.ChatMessage{
+			{Role: "system", Content: "You are terse."},
+			{Role: "user", Content: "Hello"},
+		},
+		Stop: []string{"\n\n"},
+	}
+
+	resp, err := p.Invoke(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, "Hi", resp.Content)
+	require.Equal(t, int32(14), resp.Usage.TotalTokens)
+
+	respChan, errChan := p.InvokeStream(context.Background(), req)
+	var responses []*pb.LLMStreamResponse
+	for resp := range respChan {
+		responses = append(responses, resp)
+	}
+	require.NoError(t, <-errChan)
+
+	require.Len(t, responses, 3)
+	require.Equal(t, "Hi", responses[0].Content)
+	require.Equal(t, "stop", responses[1].FinishReason)
+	require.Equal(t, int32(12), responses[2].Usage.PromptTokens)
+	require.Equal(t, int32(2), responses[2].Usage.CompletionTokens)
+}
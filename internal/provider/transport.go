@@ -0,0 +1,160 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Transport defaults used when a TransportConfig field is left at zero
+const (
+	defaultConnectTimeout      = 30 * time.Second
+	defaultTLSHandshakeTimeout = 10 * time.Second
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultMaxIdleConns        = 100
+	defaultMaxIdleConnsPerHost = 10
+)
+
+// TransportConfig holds the HTTP transport settings a provider builds its client from.
+// Zero values fall back to defaults; a zero RequestTimeout or StreamTimeout means the
+// request is bounded only by its context.
+type TransportConfig struct {
+	// ConnectTimeout bounds establishing the TCP connection
+	ConnectTimeout time.Duration
+
+	// TLSHandshakeTimeout bounds the TLS handshake
+	TLSHandshakeTimeout time.Duration
+
+	// ResponseHeaderTimeout bounds the wait for response headers after the request is sent
+	ResponseHeaderTimeout time.Duration
+
+	// IdleConnTimeout is how long idle pooled connections are kept
+	IdleConnTimeout time.Duration
+
+	// RequestTimeout bounds a whole non-streaming request, including reading the body
+	RequestTimeout time.Duration
+
+	// StreamTimeout bounds a whole streaming request, including reading the body
+	StreamTimeout time.Duration
+
+	// Proxy is the proxy to egress through; nil uses the HTTP(S)_PROXY environment variables
+	Proxy *url.URL
+
+	// TLS holds a custom CA bundle and client certificates (see LoadTLSConfig)
+	TLS *tls.Config
+
+	// MaxIdleConns limits idle connections across all hosts
+	MaxIdleConns int
+
+	// MaxIdleConnsPerHost limits idle connections per host
+	MaxIdleConnsPerHost int
+
+	// MaxConnsPerHost limits total connections per host (0 means no limit)
+	MaxConnsPerHost int
+}
+
+// WithTransport sets the HTTP transport settings for the provider
+func (c *Config) WithTransport(transport TransportConfig) *Config {
+	c.Transport = transport
+	return c
+}
+
+// NewHTTPClient builds a client for non-streaming requests from the transport settings
+func (c *Config) NewHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: c.Transport.newTransport(),
+		Timeout:   c.Transport.RequestTimeout,
+	}
+}
+
+// NewStreamingHTTPClient builds a client for streaming requests from the transport settings.
+// It shares no connections with the client returned by NewHTTPClient.
+func (c *Config) NewStreamingHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: c.Transport.newTransport(),
+		Timeout:   c.Transport.StreamTimeout,
+	}
+}
+
+// newTransport builds an http.Transport, applying defaults for unset fields
+func (t TransportConfig) newTransport() *http.Transport {
+	proxy := http.ProxyFromEnvironment
+	if t.Proxy != nil {
+		proxy = http.ProxyURL(t.Proxy)
+	}
+
+	var tlsConfig *tls.Config
+	if t.TLS != nil {
+		tlsConfig = t.TLS.Clone()
+	}
+
+	return &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   orDefault(t.ConnectTimeout, defaultConnectTimeout),
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSClientConfig:       tlsConfig,
+		ForceAttemptHTTP2:     true,
+		TLSHandshakeTimeout:   orDefault(t.TLSHandshakeTimeout, defaultTLSHandshakeTimeout),
+		ResponseHeaderTimeout: t.ResponseHeaderTimeout,
+		IdleConnTimeout:       orDefault(t.IdleConnTimeout, defaultIdleConnTimeout),
+		ExpectContinueTimeout: 1 * time.Second,
+		MaxIdleConns:          orDefault(t.MaxIdleConns, defaultMaxIdleConns),
+		MaxIdleConnsPerHost:   orDefault(t.MaxIdleConnsPerHost, defaultMaxIdleConnsPerHost),
+		MaxConnsPerHost:       t.MaxConnsPerHost,
+	}
+}
+
+// LoadTLSConfig builds a TLS configuration from PEM files. caFile adds a CA bundle to the
+// system roots (for private CAs, e.g. behind a TLS-intercepting proxy); certFile and keyFile
+// set a client certificate. Empty paths are skipped; nil is returned if all are empty.
+func LoadTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	if caFile == "" && certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, fmt.Errorf("client certificate and key must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// orDefault returns value, or fallback if value is zero
+func orDefault[T comparable](value, fallback T) T {
+	var zero T
+	if value == zero {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClientDefaults(t *testing.T) {
+	cfg := NewConfig("test-api-key", "test-model")
+
+	client := cfg.NewHTTPClient()
+	require.Zero(t, client.Timeout)
+
+	transport := client.Transport.(*http.Transport)
+	require.Equal(t, defaultTLSHandshakeTimeout, transport.TLSHandshakeTimeout)
+	require.Equal(t, defaultIdleConnTimeout, transport.IdleConnTimeout)
+	require.Equal(t, defaultMaxIdleConns, transport.MaxIdleConns)
+	require.Equal(t, defaultMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+	require.Nil(t, transport.TLSClientConfig)
+}
+
+func TestNewHTTPClientSettings(t *testing.T) {
+	cfg := NewConfig("test-api-key", "test-model").WithTransport(TransportConfig{
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: 20 * time.Second,
+		RequestTimeout:        time.Minute,
+		StreamTimeout:         10 * time.Minute,
+		MaxIdleConns:          50,
+		MaxIdleConnsPerHost:   25,
+		MaxConnsPerHost:       40,
+	})
+
+	client := cfg.NewHTTPClient()
+	require.Equal(t, time.Minute, client.Timeout)
+	require.Equal(t, 10*time.Minute, cfg.NewStreamingHTTPClient().Timeout)
+
+	transport := client.Transport.(*http.Transport)
+	require.Equal(t, 5*time.Second, transport.TLSHandshakeTimeout)
+	require.Equal(t, 20*time.Second, transport.ResponseHeaderTimeout)
+	require.Equal(t, 50, transport.MaxIdleConns)
+	require.Equal(t, 25, transport.MaxIdleConnsPerHost)
+	require.Equal(t, 40, transport.MaxConnsPerHost)
+}
+
+func TestNewHTTPClientProxy(t *testing.T) {
+	// A plain HTTP proxy receives the absolute request URL
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "api.example.invalid", r.URL.Host)
+		fmt.Fprint(w, "via proxy")
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+
+	client := NewConfig("test-api-key", "test-model").
+		WithTransport(TransportConfig{Proxy: proxyURL}).
+		NewHTTPClient()
+
+	resp, err := client.Get("http://api.example.invalid/v1/models")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestLoadTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// Without the private CA the server certificate is rejected
+	_, err := NewConfig("test-api-key", "test-model").NewHTTPClient().Get(server.URL)
+	require.Error(t, err)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: server.Certificate().Raw,
+	}), 0o600))
+
+	tlsConfig, err := LoadTLSConfig(caFile, "", "")
+	require.NoError(t, err)
+
+	client := NewConfig("test-api-key", "test-model").
+		WithTransport(TransportConfig{TLS: tlsConfig}).
+		NewHTTPClient()
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	tlsConfig, err = LoadTLSConfig("", "", "")
+	require.NoError(t, err)
+	require.Nil(t, tlsConfig)
+
+	_, err = LoadTLSConfig("", "client.pem", "")
+	require.ErrorContains(t, err, "must be set together")
+
+	_, err = LoadTLSConfig(filepath.Join(t.TempDir(), "missing.pem"), "", "")
+	require.ErrorContains(t, err, "failed to read CA bundle")
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"sort"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/c0rtexR/llm_service/internal/provider/keypool"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+// AdminServer implements the AdminServiceServer interface
+type AdminServer struct {
+	pb.UnimplementedAdminServiceServer
+	llm *LLMServer
+}
+
+// NewAdminServer creates an admin server for the given LLM server
+func NewAdminServer(llm *LLMServer) *AdminServer {
+	return &AdminServer{llm: llm}
+}
+
+// ListCircuits returns the state of every provider circuit breaker, and of the model
+// circuit breakers of models that have failed since their circuit last closed
+func (s *AdminServer) ListCircuits(ctx context.Context, req *pb.ListCircuitsRequest) (*pb.ListCircuitsResponse, error) {
+	if s.llm.circuits == nil {
+		return &pb.ListCircuitsResponse{}, nil
+	}
+	return &pb.ListCircuitsResponse{Circuits: s.llm.circuits.list()}, nil
+}
+
+// ResetCircuit closes a circuit breaker
+func (s *AdminServer) ResetCircuit(ctx context.Context, req *pb.ResetCircuitRequest) (*pb.CircuitInfo, error) {
+	if s.llm.circuits == nil {
+		return nil, status.Error(codes.FailedPrecondition, "circuit breakers are disabled")
+	}
+	info, err := s.llm.circuits.reset(circuitKey{provider: req.Provider, model: req.Model})
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return info, nil
+}
+
+// ListAPIKeys returns the state and usage of the API keys of providers with a key pool
+func (s *AdminServer) ListAPIKeys(ctx context.Context, req *pb.ListAPIKeysRequest) (*pb.ListAPIKeysResponse, error) {
+	names := make([]string, 0, len(s.llm.providers))
+	for name := range s.llm.providers {
+		if req.Provider == "" || name == req.Provider {
+			names = append(names, name)
+		}
+	}
+	if req.Provider != "" && len(names) == 0 {
+		return nil, status.Errorf(codes.NotFound, "unsupported provider: %s", req.Provider)
+	}
+	sort.Strings(names)
+
+	resp := &pb.ListAPIKeysResponse{}
+	for _, name := range names {
+		if pool, ok := keypool.AsPool(s.llm.providers[name]); ok {
+			resp.Keys = append(resp.Keys, pool.Info()...)
+		}
+	}
+	return resp, nil
+}
+
+// ListLimits returns the concurrency and queue state of every limited provider and model
+func (s *AdminServer) ListLimits(ctx context.Context, req *pb.ListLimitsRequest) (*pb.ListLimitsResponse, error) {
+	if s.llm.limits == nil {
+		return &pb.ListLimitsResponse{}, nil
+	}
+	return &pb.ListLimitsResponse{Limits: s.llm.limits.list()}, nil
+}
@@ -0,0 +1,366 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+// BreakerConfig configures a circuit breaker. Breakers trip on a run of consecutive
+// failures rather than a failure rate, so a provider that fails intermittently keeps
+// its circuit closed; the health service reports such providers from their error rate.
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit
+	FailureThreshold int
+
+	// OpenTimeout is how long the circuit stays open before admitting trial requests
+	OpenTimeout time.Duration
+
+	// HalfOpenRequests is the number of concurrent trial requests admitted while half-open
+	HalfOpenRequests int
+}
+
+// DefaultBreakerConfig returns the breaker settings used for per-model circuits.
+// Provider-wide circuits usually want a higher threshold, since they see the failures
+// of every model.
+func DefaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+		HalfOpenRequests: 1,
+	}
+}
+
+// outcome is how a finished request affects a circuit
+type outcome int
+
+const (
+	// outcomeSuccess means the provider answered, even if it rejected the request
+	outcomeSuccess outcome = iota
+	// outcomeFailure means the provider failed in a way that suggests it is unhealthy
+	outcomeFailure
+	// outcomeIgnored means the request ended without saying anything about the provider
+	outcomeIgnored
+)
+
+// classify maps a request error to its effect on the circuit. Only transient provider
+// failures and timeouts count against it; invalid requests are the caller's problem.
+func classify(err error) outcome {
+	switch {
+	case err == nil:
+		return outcomeSuccess
+	case errors.Is(err, context.Canceled):
+		return outcomeIgnored
+	case errors.Is(err, context.DeadlineExceeded), provider.IsRetryable(err):
+		return outcomeFailure
+	default:
+		return outcomeSuccess
+	}
+}
+
+// circuitBreaker guards a provider or model. Closed circuits count consecutive
+// failures and open at the threshold; open circuits reject requests until the timeout
+// passes, then admit a limited number of trial requests. A successful trial closes the
+// circuit and a failed one opens it again.
+type circuitBreaker struct {
+	config BreakerConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	state    pb.CircuitState
+	failures int
+	openedAt time.Time
+	trials   int
+}
+
+func newCircuitBreaker(config BreakerConfig, now func() time.Time) *circuitBreaker {
+	return &circuitBreaker{
+		config: config,
+		now:    now,
+		state:  pb.CircuitState_CIRCUIT_STATE_CLOSED,
+	}
+}
+
+// allow reports whether a request may proceed, and whether it is a half-open trial.
+// Admitted requests must be reported with record.
+func (b *circuitBreaker) allow() (trial bool, retryAt time.Time, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case pb.CircuitState_CIRCUIT_STATE_OPEN:
+		retryAt = b.openedAt.Add(b.config.OpenTimeout)
+		if b.now().Before(retryAt) {
+			return false, retryAt, false
+		}
+		b.state = pb.CircuitState_CIRCUIT_STATE_HALF_OPEN
+		b.trials = 0
+		fallthrough
+	case pb.CircuitState_CIRCUIT_STATE_HALF_OPEN:
+		if b.trials >= max(b.config.HalfOpenRequests, 1) {
+			return false, b.now(), false
+		}
+		b.trials++
+		return true, time.Time{}, true
+	default:
+		return false, time.Time{}, true
+	}
+}
+
+// record applies the outcome of an admitted request. Results of requests admitted in
+// an earlier state are dropped so a slow request cannot undo a newer transition.
+func (b *circuitBreaker) record(trial bool, result outcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if trial {
+		if b.state != pb.CircuitState_CIRCUIT_STATE_HALF_OPEN {
+			return
+		}
+		b.trials--
+		switch result {
+		case outcomeSuccess:
+			b.reset()
+		case outcomeFailure:
+			b.trip()
+		}
+		return
+	}
+
+	if b.state != pb.CircuitState_CIRCUIT_STATE_CLOSED {
+		return
+	}
+	switch result {
+	case outcomeSuccess:
+		b.failures = 0
+	case outcomeFailure:
+		b.failures++
+		if b.failures >= b.config.FailureThreshold {
+			b.trip()
+		}
+	}
+}
+
+// trip opens the circuit; the caller must hold the lock
+func (b *circuitBreaker) trip() {
+	b.state = pb.CircuitState_CIRCUIT_STATE_OPEN
+	b.openedAt = b.now()
+	b.trials = 0
+}
+
+// reset closes the circuit; the caller must hold the lock
+func (b *circuitBreaker) reset() {
+	b.state = pb.CircuitState_CIRCUIT_STATE_CLOSED
+	b.failures = 0
+	b.trials = 0
+}
+
+// idle reports whether the circuit is closed with no failures or requests to account for
+func (b *circuitBreaker) idle() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == pb.CircuitState_CIRCUIT_STATE_CLOSED && b.failures == 0 && b.trials == 0
+}
+
+// info returns the circuit's current state
+func (b *circuitBreaker) info(key circuitKey) *pb.CircuitInfo {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	info := &pb.CircuitInfo{
+		Provider:            key.provider,
+		Model:               key.model,
+		State:               b.state,
+		ConsecutiveFailures: int32(b.failures),
+	}
+	if !b.openedAt.IsZero() {
+		info.OpenedAt = b.openedAt.Unix()
+	}
+	if b.state == pb.CircuitState_CIRCUIT_STATE_OPEN {
+		info.RetryAt = b.openedAt.Add(b.config.OpenTimeout).Unix()
+	}
+	return info
+}
+
+// circuitKey identifies a circuit; an empty model is the provider-wide circuit
+type circuitKey struct {
+	provider string
+	model    string
+}
+
+func (k circuitKey) String() string {
+	if k.model == "" {
+		return k.provider
+	}
+	return k.provider + "/" + k.model
+}
+
+// circuits holds the provider-wide and per-model circuit breakers. Model breakers are
+// only kept while they have something to report: one is created when a model first
+// fails and dropped once a success closes it, so the model names clients send cannot
+// grow the set without bound.
+type circuits struct {
+	providerConfig BreakerConfig
+	modelConfig    BreakerConfig
+	now            func() time.Time
+
+	mu       sync.Mutex
+	breakers map[circuitKey]*circuitBreaker
+}
+
+func newCircuits(providerConfig, modelConfig BreakerConfig) *circuits {
+	return &circuits{
+		providerConfig: providerConfig,
+		modelConfig:    modelConfig,
+		now:            time.Now,
+		breakers:       make(map[circuitKey]*circuitBreaker),
+	}
+}
+
+// lookup returns the breaker for key, or nil if it is not tracked
+func (c *circuits) lookup(key circuitKey) *circuitBreaker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.breakers[key]
+}
+
+// breaker returns the breaker for key, creating it if needed
+func (c *circuits) breaker(key circuitKey) *circuitBreaker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, ok := c.breakers[key]
+	if !ok {
+		config := c.modelConfig
+		if key.model == "" {
+			config = c.providerConfig
+		}
+		b = newCircuitBreaker(config, c.now)
+		c.breakers[key] = b
+	}
+	return b
+}
+
+// forget drops a model breaker that is closed with nothing to report
+func (c *circuits) forget(key circuitKey, b *circuitBreaker) {
+	if key.model == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.breakers[key] == b && b.idle() {
+		delete(c.breakers, key)
+	}
+}
+
+// acquire admits a request through the provider and model circuits. It fails with
+// codes.Unavailable if either is open; otherwise the returned release must be called
+// with the request's final error.
+func (c *circuits) acquire(providerName, model string) (release func(error), err error) {
+	providerKey := circuitKey{provider: providerName}
+	modelKey := circuitKey{provider: providerName, model: model}
+
+	type admitted struct {
+		key     circuitKey
+		breaker *circuitBreaker
+		trial   bool
+	}
+	var held []admitted
+	for _, key := range []circuitKey{providerKey, modelKey} {
+		var b *circuitBreaker
+		if key.model == "" {
+			b = c.breaker(key)
+		} else if b = c.lookup(key); b == nil {
+			// Untracked models are closed; a failure starts tracking them
+			continue
+		}
+		trial, retryAt, ok := b.allow()
+		if !ok {
+			// Give back trial slots taken on the circuits already passed
+			for _, a := range held {
+				a.breaker.record(a.trial, outcomeIgnored)
+			}
+			return nil, status.Errorf(codes.Unavailable, "circuit open for %s: retry after %s",
+				key, retryAt.UTC().Format(time.RFC3339))
+		}
+		held = append(held, admitted{key: key, breaker: b, trial: trial})
+	}
+
+	return func(err error) {
+		result := classify(err)
+		if model != "" && len(held) == 1 && result == outcomeFailure {
+			held = append(held, admitted{key: modelKey, breaker: c.breaker(modelKey)})
+		}
+		for _, a := range held {
+			a.breaker.record(a.trial, result)
+			if result == outcomeSuccess {
+				c.forget(a.key, a.breaker)
+			}
+		}
+	}, nil
+}
+
+// state returns the state of a tracked circuit
+func (c *circuits) state(key circuitKey) (pb.CircuitState, bool) {
+	c.mu.Lock()
+	b, ok := c.breakers[key]
+	c.mu.Unlock()
+	if !ok {
+		return pb.CircuitState_CIRCUIT_STATE_UNSPECIFIED, false
+	}
+	return b.info(key).State, true
+}
+
+// list returns every circuit, provider-wide circuits first
+func (c *circuits) list() []*pb.CircuitInfo {
+	c.mu.Lock()
+	keys := make([]circuitKey, 0, len(c.breakers))
+	breakers := make(map[circuitKey]*circuitBreaker, len(c.breakers))
+	for key, b := range c.breakers {
+		keys = append(keys, key)
+		breakers[key] = b
+	}
+	c.mu.Unlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		if (keys[i].model == "") != (keys[j].model == "") {
+			return keys[i].model == ""
+		}
+		if keys[i].provider != keys[j].provider {
+			return keys[i].provider < keys[j].provider
+		}
+		return keys[i].model < keys[j].model
+	})
+
+	infos := make([]*pb.CircuitInfo, len(keys))
+	for i, key := range keys {
+		infos[i] = breakers[key].info(key)
+	}
+	return infos
+}
+
+// reset closes a tracked circuit
+func (c *circuits) reset(key circuitKey) (*pb.CircuitInfo, error) {
+	c.mu.Lock()
+	b, ok := c.breakers[key]
+	c.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown circuit: %s", key)
+	}
+
+	b.mu.Lock()
+	b.reset()
+	b.mu.Unlock()
+	info := b.info(key)
+	c.forget(key, b)
+	return info, nil
+}
@@ -0,0 +1,280 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+// fakeClock is a manually advanced clock for circuit timeouts
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+var (
+	errOverloaded = &provider.APIError{StatusCode: provider.StatusOverloaded, Body: "overloaded"}
+	errBadRequest = &provider.APIError{StatusCode: http.StatusBadRequest, Body: "invalid model"}
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	b := newCircuitBreaker(BreakerConfig{
+		FailureThreshold: 3,
+		OpenTimeout:      30 * time.Second,
+		HalfOpenRequests: 1,
+	}, clock.Now)
+
+	fail := func() {
+		trial, _, ok := b.allow()
+		require.True(t, ok)
+		b.record(trial, outcomeFailure)
+	}
+
+	// A success resets the failure count
+	fail()
+	fail()
+	_, _, ok := b.allow()
+	require.True(t, ok)
+	b.record(false, outcomeSuccess)
+	require.Equal(t, int32(0), b.info(circuitKey{}).ConsecutiveFailures)
+
+	// Consecutive failures open the circuit
+	fail()
+	fail()
+	fail()
+	require.Equal(t, pb.CircuitState_CIRCUIT_STATE_OPEN, b.info(circuitKey{}).State)
+	_, retryAt, ok := b.allow()
+	require.False(t, ok)
+	require.Equal(t, clock.now.Add(30*time.Second), retryAt)
+
+	// After the timeout one trial request is admitted at a time
+	clock.now = clock.now.Add(31 * time.Second)
+	trial, _, ok := b.allow()
+	require.True(t, ok)
+	require.True(t, trial)
+	_, _, ok = b.allow()
+	require.False(t, ok)
+
+	// A failed trial reopens the circuit
+	b.record(trial, outcomeFailure)
+	require.Equal(t, pb.CircuitState_CIRCUIT_STATE_OPEN, b.info(circuitKey{}).State)
+
+	// A successful trial closes it
+	clock.now = clock.now.Add(31 * time.Second)
+	trial, _, ok = b.allow()
+	require.True(t, ok)
+	b.record(trial, outcomeSuccess)
+	require.Equal(t, pb.CircuitState_CIRCUIT_STATE_CLOSED, b.info(circuitKey{}).State)
+}
+
+func TestCircuitBreakerIgnoresStaleResults(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	b := newCircuitBreaker(BreakerConfig{FailureThreshold: 1, OpenTimeout: time.Second, HalfOpenRequests: 1}, clock.Now)
+
+	// A slow request admitted while closed finishes after the circuit opened
+	slowTrial, _, _ := b.allow()
+	trial, _, _ := b.allow()
+	b.record(trial, outcomeFailure)
+	b.record(slowTrial, outcomeSuccess)
+	require.Equal(t, pb.CircuitState_CIRCUIT_STATE_OPEN, b.info(circuitKey{}).State)
+
+	// A canceled trial frees its slot without changing the state
+	clock.now = clock.now.Add(2 * time.Second)
+	trial, _, ok := b.allow()
+	require.True(t, ok)
+	b.record(trial, outcomeIgnored)
+	require.Equal(t, pb.CircuitState_CIRCUIT_STATE_HALF_OPEN, b.info(circuitKey{}).State)
+	_, _, ok = b.allow()
+	require.True(t, ok)
+}
+
+func TestClassify(t *testing.T) {
+	require.Equal(t, outcomeSuccess, classify(nil))
+	require.Equal(t, outcomeSuccess, classify(errBadRequest))
+	require.Equal(t, outcomeFailure, classify(errOverloaded))
+	require.Equal(t, outcomeFailure, classify(context.DeadlineExceeded))
+	require.Equal(t, outcomeIgnored, classify(context.Canceled))
+}
+
+// newCircuitTestServer returns a server whose model circuits open after two failures
+func newCircuitTestServer(m *mockProvider) (*LLMServer, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	s := New(map[string]provider.LLMProvider{"test": m},
+		WithCircuitBreakers(
+			BreakerConfig{FailureThreshold: 10, OpenTimeout: time.Minute, HalfOpenRequests: 1},
+			BreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute, HalfOpenRequests: 1},
+		))
+	s.circuits.now = clock.Now
+	return s, clock
+}
+
+func TestLLMServer_CircuitBreaker(t *testing.T) {
+	m := &mockProvider{}
+	m.On("Invoke", mock.Anything, mock.MatchedBy(func(req *pb.LLMRequest) bool {
+		return req.Model == "broken"
+	})).Return(nil, errOverloaded)
+	m.On("Invoke", mock.Anything, mock.Anything).Return(&pb.LLMResponse{Content: "ok"}, nil)
+
+	s, clock := newCircuitTestServer(m)
+	broken := &pb.LLMRequest{Provider: "test", Model: "broken"}
+
+	for i := 0; i < 2; i++ {
+		_, err := s.Invoke(context.Background(), broken)
+		require.ErrorIs(t, err, errOverloaded)
+	}
+
+	// The open circuit fails fast without calling the provider
+	_, err := s.Invoke(context.Background(), broken)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.ErrorContains(t, err, "circuit open for test/broken")
+	m.AssertNumberOfCalls(t, "Invoke", 2)
+
+	// Other models of the provider are unaffected
+	resp, err := s.Invoke(context.Background(), &pb.LLMRequest{Provider: "test", Model: "healthy"})
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp.Content)
+
+	// Health reports the open circuit
+	health := NewHealthServer().WithLLMServer(s)
+	check := func(service string) grpc_health_v1.HealthCheckResponse_ServingStatus {
+		resp, err := health.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		return resp.Status
+	}
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, check(""))
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, check("test"))
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, check("test/broken"))
+	_, err = health.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "unknown"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// After the timeout a trial request reaches the provider again
+	clock.now = clock.now.Add(2 * time.Minute)
+	_, err = s.Invoke(context.Background(), broken)
+	require.ErrorIs(t, err, errOverloaded)
+	m.AssertNumberOfCalls(t, "Invoke", 4)
+}
+
+func TestLLMServer_CircuitBreakerIgnoresExpiredDeadline(t *testing.T) {
+	m := &mockProvider{}
+	m.On("Invoke", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	}).Return(nil, context.DeadlineExceeded)
+
+	s, _ := newCircuitTestServer(m)
+	req := &pb.LLMRequest{Provider: "test", Model: "slow"}
+
+	// A client that gives up too soon does not open the circuit for everyone else
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		_, err := s.Invoke(ctx, req)
+		cancel()
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	}
+	state, _ := s.circuits.state(circuitKey{provider: "test", model: "slow"})
+	require.NotEqual(t, pb.CircuitState_CIRCUIT_STATE_OPEN, state)
+	m.AssertNumberOfCalls(t, "Invoke", 3)
+}
+
+func TestCircuits_TracksFailingModelsOnly(t *testing.T) {
+	c := newCircuits(DefaultBreakerConfig(), DefaultBreakerConfig())
+
+	// Models that only succeed are not tracked, however many clients send
+	for i := 0; i < 100; i++ {
+		release, err := c.acquire("test", fmt.Sprintf("model-%d", i))
+		require.NoError(t, err)
+		release(nil)
+	}
+	require.Len(t, c.list(), 1)
+
+	// A failing model is tracked until a success closes its circuit again
+	release, err := c.acquire("test", "flaky")
+	require.NoError(t, err)
+	release(errOverloaded)
+	state, ok := c.state(circuitKey{provider: "test", model: "flaky"})
+	require.True(t, ok)
+	require.Equal(t, pb.CircuitState_CIRCUIT_STATE_CLOSED, state)
+	require.Len(t, c.list(), 2)
+
+	release, err = c.acquire("test", "flaky")
+	require.NoError(t, err)
+	release(nil)
+	_, ok = c.state(circuitKey{provider: "test", model: "flaky"})
+	require.False(t, ok)
+	require.Len(t, c.list(), 1)
+}
+
+func TestLLMServer_CircuitBreakerStream(t *testing.T) {
+	m := &mockProvider{}
+	onStream(m, fakeStream{err: errOverloaded}).Times(2)
+
+	s, _ := newCircuitTestServer(m)
+	req := &pb.LLMRequest{Provider: "test", Model: "broken"}
+
+	for i := 0; i < 2; i++ {
+		err := s.InvokeStream(req, recordStream())
+		require.ErrorIs(t, err, errOverloaded)
+	}
+
+	err := s.InvokeStream(req, recordStream())
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	m.AssertExpectations(t)
+}
+
+func TestAdminServer(t *testing.T) {
+	m := &mockProvider{}
+	m.On("Invoke", mock.Anything, mock.Anything).Return(nil, errOverloaded)
+
+	s, _ := newCircuitTestServer(m)
+	admin := NewAdminServer(s)
+
+	for i := 0; i < 2; i++ {
+		_, err := s.Invoke(context.Background(), &pb.LLMRequest{Provider: "test", Model: "broken"})
+		require.Error(t, err)
+	}
+
+	resp, err := admin.ListCircuits(context.Background(), &pb.ListCircuitsRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Circuits, 2)
+
+	// Provider-wide circuits are listed first
+	require.Equal(t, "test", resp.Circuits[0].Provider)
+	require.Empty(t, resp.Circuits[0].Model)
+	require.Equal(t, pb.CircuitState_CIRCUIT_STATE_CLOSED, resp.Circuits[0].State)
+	require.Equal(t, int32(2), resp.Circuits[0].ConsecutiveFailures)
+
+	require.Equal(t, "broken", resp.Circuits[1].Model)
+	require.Equal(t, pb.CircuitState_CIRCUIT_STATE_OPEN, resp.Circuits[1].State)
+	require.Equal(t, resp.Circuits[1].OpenedAt+60, resp.Circuits[1].RetryAt)
+
+	info, err := admin.ResetCircuit(context.Background(), &pb.ResetCircuitRequest{Provider: "test", Model: "broken"})
+	require.NoError(t, err)
+	require.Equal(t, pb.CircuitState_CIRCUIT_STATE_CLOSED, info.State)
+
+	_, err = admin.ResetCircuit(context.Background(), &pb.ResetCircuitRequest{Provider: "test", Model: "unknown"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// Providers without a key pool have no keys to list
+	keys, err := admin.ListAPIKeys(context.Background(), &pb.ListAPIKeysRequest{})
+	require.NoError(t, err)
+	require.Empty(t, keys.Keys)
+	_, err = admin.ListAPIKeys(context.Background(), &pb.ListAPIKeysRequest{Provider: "unknown"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// Without circuit breakers the admin API reports nothing
+	resp, err = NewAdminServer(New(nil)).ListCircuits(context.Background(), &pb.ListCircuitsRequest{})
+	require.NoError(t, err)
+	require.Empty(t, resp.Circuits)
+}
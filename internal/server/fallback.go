@@ -0,0 +1,241 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+// Target is a provider/model pair in a fallback chain
+type Target struct {
+	Provider string
+	// Model is empty for the provider's default model
+	Model string
+}
+
+func (t Target) String() string {
+	if t.Model == "" {
+		return t.Provider
+	}
+	return t.Provider + "/" + t.Model
+}
+
+// ParseRoute parses a comma-separated fallback chain of provider/model pairs, e.g.
+// "anthropic/claude-3-5-haiku-latest,openai/gpt-4o-mini,openrouter/meta-llama/llama-3.1-8b-instruct".
+// Everything after the first slash is the model, and a bare provider uses its default model.
+func ParseRoute(spec string) ([]Target, error) {
+	var route []Target
+	for _, hop := range strings.Split(spec, ",") {
+		hop = strings.TrimSpace(hop)
+		if hop == "" {
+			continue
+		}
+		providerName, model, _ := strings.Cut(hop, "/")
+		if providerName == "" {
+			return nil, fmt.Errorf("invalid route hop %q: expected provider/model", hop)
+		}
+		route = append(route, Target{Provider: providerName, Model: model})
+	}
+	if len(route) == 0 {
+		return nil, fmt.Errorf("empty route")
+	}
+	return route, nil
+}
+
+// WithRoutes registers named fallback chains, selected with LLMRequest.route
+func WithRoutes(routes map[string][]Target) Option {
+	return func(s *LLMServer) {
+		s.routes = routes
+	}
+}
+
+// hop is a resolved step of a fallback chain
+type hop struct {
+	target   Target
+	provider provider.LLMProvider
+}
+
+// resolveChain returns the hops to try for a request: the named route, or the request's
+// provider and model, followed by the request's fallbacks. Every provider is checked up
+// front so a misconfigured hop fails the request rather than only the unlucky ones.
+func (s *LLMServer) resolveChain(req *pb.LLMRequest) ([]hop, error) {
+	targets := []Target{{Provider: req.Provider, Model: req.Model}}
+	if req.Route != "" {
+		route, ok := s.routes[req.Route]
+		if !ok {
+			return nil, fmt.Errorf("unknown route: %s", req.Route)
+		}
+		targets = route
+	}
+	for _, fallback := range req.Fallbacks {
+		targets = append(targets, Target{Provider: fallback.Provider, Model: fallback.Model})
+	}
+
+	chain := make([]hop, len(targets))
+	for i, target := range targets {
+		p, err := s.getProvider(target.Provider)
+		if err != nil {
+			return nil, err
+		}
+		chain[i] = hop{target: target, provider: p}
+	}
+	return chain, nil
+}
+
+// hopRequest returns the request for the nth hop of the chain. The primary hop of a
+// plain request gets the request as sent; every other hop gets a copy addressed to its
+// provider and model, translated if it is a fallback.
+func hopRequest(req *pb.LLMRequest, chain []hop, n int) *pb.LLMRequest {
+	if n == 0 && req.Route == "" {
+		return req
+	}
+
+	hopReq := proto.Clone(req).(*pb.LLMRequest)
+	hopReq.Provider = chain[n].target.Provider
+	hopReq.Model = chain[n].target.Model
+	hopReq.Route = ""
+	hopReq.Fallbacks = nil
+	if n > 0 {
+		translate(hopReq)
+	}
+	return hopReq
+}
+
+// prefillProviders holds the providers that continue a trailing assistant message
+// rather than answering after it
+var prefillProviders = map[string]bool{
+	"anthropic": true,
+}
+
+// continuePrompt asks providers without prefill support to pick up a partial answer
+const continuePrompt = "Your previous response was cut off. Continue it exactly where it stopped, without repeating any of it."
+
+// continuation returns the request for the nth hop to carry on a stream that failed
+// after sending partial. The partial answer is sent as an assistant prefill, followed by
+// a request to continue for providers that cannot continue a prefill.
+func continuation(req *pb.LLMRequest, chain []hop, n int, partial string) *pb.LLMRequest {
+	hopReq := hopRequest(req, chain, n)
+	if partial == "" {
+		return hopReq
+	}
+
+	if prefillProviders[hopReq.Provider] {
+		// Anthropic rejects a final assistant turn that ends in whitespace
+		partial = strings.TrimRight(partial, " \t\r\n")
+	}
+	hopReq.Messages = append(hopReq.Messages, &pb.ChatMessage{Role: "assistant", Content: partial})
+	if !prefillProviders[hopReq.Provider] {
+		hopReq.Messages = append(hopReq.Messages, &pb.ChatMessage{Role: "user", Content: continuePrompt})
+	}
+	return hopReq
+}
+
+// transcript records what a stream has sent, so a fallback can continue it. A marker set
+// before a hop starts is sent ahead of that hop's first chunk.
+type transcript struct {
+	chunkSender
+	sent    int
+	content strings.Builder
+	marker  *pb.LLMStreamResponse
+}
+
+func (t *transcript) Send(resp *pb.LLMStreamResponse) error {
+	if t.marker != nil {
+		if err := t.chunkSender.Send(t.marker); err != nil {
+			return err
+		}
+		t.marker = nil
+	}
+	if err := t.chunkSender.Send(resp); err != nil {
+		return err
+	}
+	t.sent++
+	if resp.Type == pb.ResponseType_TYPE_CONTENT {
+		t.content.WriteString(resp.Content)
+	}
+	return nil
+}
+
+// maxTemperature holds the providers whose temperature range is narrower than the
+// 0-2 accepted by OpenAI-compatible APIs
+var maxTemperature = map[string]float32{
+	"anthropic": 1,
+}
+
+// translate adapts the parameters of a request written for one provider to the
+// provider in req.Provider. Options specific to another provider are dropped, since
+// they either mean nothing to the fallback or would be rejected by it.
+func translate(req *pb.LLMRequest) {
+	if limit, ok := maxTemperature[req.Provider]; ok && req.Temperature > limit {
+		req.Temperature = limit
+	}
+	if req.Provider != "openai" {
+		// Stored conversations and reasoning effort only exist on OpenAI
+		req.PreviousResponseId = ""
+		req.ReasoningEffort = ""
+	}
+	if req.Provider != "gemini" {
+		req.SafetySettings = nil
+	}
+	if req.Provider != "openrouter" {
+		req.Openrouter = nil
+	}
+	if req.Provider != "tgi" {
+		req.RepetitionPenalty = 0
+	}
+}
+
+// canFallBack reports whether a failed hop should give way to the next one: the
+// provider failed transiently, was too slow, its circuit is open or its queue is full,
+// and the caller is still waiting
+func canFallBack(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var timeout *timeoutError
+	if errors.As(err, &timeout) {
+		return timeout.hopTimeout()
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted:
+		return true
+	}
+	return provider.IsRetryable(err)
+}
+
+// servedBy describes the nth hop of the chain for the response
+func servedBy(chain []hop, n int) *pb.ServedBy {
+	return &pb.ServedBy{
+		Provider: chain[n].target.Provider,
+		Model:    chain[n].target.Model,
+		Hop:      int32(n),
+	}
+}
+
+// logFailover records that the nth hop failed mid-stream and the next one continues it
+func logFailover(chain []hop, n int, partial int, err error) {
+	zap.L().Warn("continuing stream on next provider",
+		zap.Stringer("failed", chain[n].target),
+		zap.Stringer("next", chain[n+1].target),
+		zap.Int("hop", n+1),
+		zap.Int("partial_bytes", partial),
+		zap.Error(err))
+}
+
+// logFallback records that the nth hop failed and the next one takes over
+func logFallback(chain []hop, n int, err error) {
+	zap.L().Warn("falling back to next provider",
+		zap.Stringer("failed", chain[n].target),
+		zap.Stringer("next", chain[n+1].target),
+		zap.Int("hop", n+1),
+		zap.Error(err))
+}
@@ -0,0 +1,265 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	"github.com/c0rtexR/llm_service/internal/provider/anthropic"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+func TestParseRoute(t *testing.T) {
+	route, err := ParseRoute("anthropic/claude-3-5-haiku-latest, openai/gpt-4o-mini,openrouter/meta-llama/llama-3.1-8b-instruct,tgi,")
+	require.NoError(t, err)
+	require.Equal(t, []Target{
+		{Provider: "anthropic", Model: "claude-3-5-haiku-latest"},
+		{Provider: "openai", Model: "gpt-4o-mini"},
+		{Provider: "openrouter", Model: "meta-llama/llama-3.1-8b-instruct"},
+		{Provider: "tgi"},
+	}, route)
+
+	_, err = ParseRoute("/gpt-4o-mini")
+	require.ErrorContains(t, err, "expected provider/model")
+
+	_, err = ParseRoute(" , ")
+	require.ErrorContains(t, err, "empty route")
+}
+
+// newFallbackTestServer returns a server with anthropic and openai mocks and a "fast" route
+func newFallbackTestServer(opts ...Option) (*LLMServer, *mockProvider, *mockProvider) {
+	anthropic, openai := &mockProvider{}, &mockProvider{}
+	opts = append(opts, WithRoutes(map[string][]Target{
+		"fast": {
+			{Provider: "anthropic", Model: "claude-3-5-haiku-latest"},
+			{Provider: "openai", Model: "gpt-4o-mini"},
+		},
+	}))
+	s := New(map[string]provider.LLMProvider{"anthropic": anthropic, "openai": openai}, opts...)
+	return s, anthropic, openai
+}
+
+// requireServedBy checks the hop recorded on a response
+func requireServedBy(t *testing.T, servedBy *pb.ServedBy, providerName, model string, hop int32) {
+	t.Helper()
+	require.NotNil(t, servedBy)
+	require.Equal(t, providerName, servedBy.Provider)
+	require.Equal(t, model, servedBy.Model)
+	require.Equal(t, hop, servedBy.Hop)
+}
+
+func TestLLMServer_InvokeFallback(t *testing.T) {
+	s, anthropic, openai := newFallbackTestServer()
+	anthropic.On("Invoke", mock.Anything, mock.Anything).Return(nil, errOverloaded)
+	openai.On("Invoke", mock.Anything, mock.MatchedBy(func(req *pb.LLMRequest) bool {
+		return req.Provider == "openai" && req.Model == "gpt-4o-mini" && req.Route == ""
+	})).Return(&pb.LLMResponse{Content: "ok"}, nil)
+
+	resp, err := s.Invoke(context.Background(), &pb.LLMRequest{Route: "fast"})
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp.Content)
+	requireServedBy(t, resp.ServedBy, "openai", "gpt-4o-mini", 1)
+
+	// Request-level fallbacks follow the request's own provider
+	resp, err = s.Invoke(context.Background(), &pb.LLMRequest{
+		Provider:  "anthropic",
+		Model:     "claude-3-5-sonnet-latest",
+		Fallbacks: []*pb.FallbackTarget{{Provider: "openai", Model: "gpt-4o-mini"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), resp.ServedBy.Hop)
+	anthropic.AssertNumberOfCalls(t, "Invoke", 2)
+}
+
+func TestLLMServer_InvokeFallbackStopsOnPermanentErrors(t *testing.T) {
+	s, anthropic, openai := newFallbackTestServer()
+	anthropic.On("Invoke", mock.Anything, mock.Anything).Return(nil, errBadRequest)
+
+	_, err := s.Invoke(context.Background(), &pb.LLMRequest{Route: "fast"})
+	require.ErrorIs(t, err, errBadRequest)
+	openai.AssertNotCalled(t, "Invoke", mock.Anything, mock.Anything)
+
+	// The last hop's error is returned when every hop fails
+	openai.On("Invoke", mock.Anything, mock.Anything).Return(nil, errOverloaded)
+	_, err = s.Invoke(context.Background(), &pb.LLMRequest{
+		Provider:  "openai",
+		Fallbacks: []*pb.FallbackTarget{{Provider: "openai", Model: "gpt-4o"}},
+	})
+	require.ErrorIs(t, err, errOverloaded)
+	openai.AssertNumberOfCalls(t, "Invoke", 2)
+}
+
+func TestLLMServer_InvokeFallbackSkipsOpenCircuits(t *testing.T) {
+	s, anthropic, openai := newFallbackTestServer(WithCircuitBreakers(
+		BreakerConfig{FailureThreshold: 10, OpenTimeout: time.Minute, HalfOpenRequests: 1},
+		BreakerConfig{FailureThreshold: 1, OpenTimeout: time.Minute, HalfOpenRequests: 1},
+	))
+	anthropic.On("Invoke", mock.Anything, mock.Anything).Return(nil, errOverloaded)
+	openai.On("Invoke", mock.Anything, mock.Anything).Return(&pb.LLMResponse{Content: "ok"}, nil)
+
+	for i := 0; i < 2; i++ {
+		resp, err := s.Invoke(context.Background(), &pb.LLMRequest{Route: "fast"})
+		require.NoError(t, err)
+		require.Equal(t, "openai", resp.ServedBy.Provider)
+	}
+
+	// The second request found the anthropic circuit open and went straight to openai
+	anthropic.AssertNumberOfCalls(t, "Invoke", 1)
+}
+
+func TestLLMServer_InvokeFallbackValidatesChain(t *testing.T) {
+	s, _, _ := newFallbackTestServer()
+
+	_, err := s.Invoke(context.Background(), &pb.LLMRequest{Route: "unknown"})
+	require.ErrorContains(t, err, "unknown route: unknown")
+
+	_, err = s.Invoke(context.Background(), &pb.LLMRequest{
+		Provider:  "anthropic",
+		Fallbacks: []*pb.FallbackTarget{{Provider: "mistral"}},
+	})
+	require.ErrorContains(t, err, "unsupported provider: mistral")
+	require.NotEqual(t, codes.Unavailable, status.Code(err))
+}
+
+func TestHopRequestTranslatesParameters(t *testing.T) {
+	req := &pb.LLMRequest{
+		Provider:           "openai",
+		Model:              "gpt-4o",
+		Temperature:        1.5,
+		MaxTokens:          100,
+		Messages:           []*pb.ChatMessage{{Role: "user", Content: "hi"}},
+		PreviousResponseId: "resp_123",
+		ReasoningEffort:    "high",
+		SafetySettings:     map[string]string{"HARM_CATEGORY_HARASSMENT": "BLOCK_NONE"},
+		Openrouter:         &pb.OpenRouterOptions{ProviderOrder: []string{"Together"}},
+		RepetitionPenalty:  1.2,
+		Fallbacks:          []*pb.FallbackTarget{{Provider: "anthropic", Model: "claude-3-5-haiku-latest"}},
+	}
+	chain := []hop{
+		{target: Target{Provider: "openai", Model: "gpt-4o"}},
+		{target: Target{Provider: "anthropic", Model: "claude-3-5-haiku-latest"}},
+	}
+
+	// The primary hop gets the request as sent
+	require.Same(t, req, hopRequest(req, chain, 0))
+
+	hopReq := hopRequest(req, chain, 1)
+	require.Equal(t, "anthropic", hopReq.Provider)
+	require.Equal(t, "claude-3-5-haiku-latest", hopReq.Model)
+	require.Equal(t, float32(1), hopReq.Temperature)
+	require.Equal(t, int32(100), hopReq.MaxTokens)
+	require.Len(t, hopReq.Messages, 1)
+	require.Empty(t, hopReq.PreviousResponseId)
+	require.Empty(t, hopReq.ReasoningEffort)
+	require.Nil(t, hopReq.SafetySettings)
+	require.Nil(t, hopReq.Openrouter)
+	require.Zero(t, hopReq.RepetitionPenalty)
+	require.Nil(t, hopReq.Fallbacks)
+
+	// The original request is left untouched
+	require.Equal(t, float32(1.5), req.Temperature)
+	require.Equal(t, "resp_123", req.PreviousResponseId)
+}
+
+func TestLLMServer_InvokeStreamFallback(t *testing.T) {
+	s, anthropic, openai := newFallbackTestServer()
+	onStream(anthropic, fakeStream{err: errOverloaded})
+	onStream(openai, fakeStream{chunks: []string{"hello", " world"}})
+
+	stream := recordStream()
+	require.NoError(t, s.InvokeStream(&pb.LLMRequest{Route: "fast"}, stream))
+	require.Len(t, stream.chunks, 2)
+	requireServedBy(t, stream.chunks[0].ServedBy, "openai", "gpt-4o-mini", 1)
+	require.Nil(t, stream.chunks[1].ServedBy)
+}
+
+func TestLLMServer_InvokeStreamFailover(t *testing.T) {
+	s, anthropic, openai := newFallbackTestServer()
+	onStream(anthropic, fakeStream{chunks: []string{"The answer is "}, err: errOverloaded})
+	var continued *pb.LLMRequest
+	onStream(openai, fakeStream{chunks: []string{"42."}}).Run(func(args mock.Arguments) {
+		continued = args.Get(1).(*pb.LLMRequest)
+	})
+
+	stream := recordStream()
+	err := s.InvokeStream(&pb.LLMRequest{
+		Route:    "fast",
+		Messages: []*pb.ChatMessage{{Role: "user", Content: "What is the answer?"}},
+	}, stream)
+	require.NoError(t, err)
+
+	// The client sees one answer with a marker where the fallback took over
+	require.Len(t, stream.chunks, 3)
+	require.Equal(t, "The answer is ", stream.chunks[0].Content)
+	require.Equal(t, pb.ResponseType_TYPE_FAILOVER, stream.chunks[1].Type)
+	requireServedBy(t, stream.chunks[1].ServedBy, "openai", "gpt-4o-mini", 1)
+	require.Equal(t, "42.", stream.chunks[2].Content)
+	require.Nil(t, stream.chunks[2].ServedBy)
+
+	// The fallback is asked to continue the partial answer
+	require.Len(t, continued.Messages, 3)
+	require.Equal(t, "assistant", continued.Messages[1].Role)
+	require.Equal(t, "The answer is ", continued.Messages[1].Content)
+	require.Equal(t, continuePrompt, continued.Messages[2].Content)
+}
+
+func TestLLMServer_InvokeStreamFailoverOnTruncatedStream(t *testing.T) {
+	// Anthropic's connection closes mid-answer, before message_stop
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"id\":\"msg_1\",\"usage\":{\"input_tokens\":5}}}\n\n")
+		fmt.Fprint(w, "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"The answer is \"}}\n\n")
+	}))
+	defer upstream.Close()
+
+	openai := &mockProvider{}
+	onStream(openai, fakeStream{chunks: []string{"42."}})
+	s := New(map[string]provider.LLMProvider{
+		"anthropic": anthropic.New(provider.NewConfig("test-key", "claude-3-5-haiku-latest").WithBaseURL(upstream.URL)),
+		"openai":    openai,
+	}, WithRoutes(map[string][]Target{
+		"fast": {{Provider: "anthropic"}, {Provider: "openai", Model: "gpt-4o-mini"}},
+	}))
+
+	stream := recordStream()
+	err := s.InvokeStream(&pb.LLMRequest{
+		Route:    "fast",
+		Messages: []*pb.ChatMessage{{Role: "user", Content: "What is the answer?"}},
+	}, stream)
+	require.NoError(t, err)
+	require.Equal(t, []string{"The answer is ", "", "42."}, stream.content())
+	require.Equal(t, pb.ResponseType_TYPE_FAILOVER, stream.chunks[1].Type)
+}
+
+func TestLLMServer_InvokeStreamNoFailoverOnPermanentError(t *testing.T) {
+	s, anthropic, openai := newFallbackTestServer()
+	onStream(anthropic, fakeStream{chunks: []string{"partial"}, err: errBadRequest})
+
+	err := s.InvokeStream(&pb.LLMRequest{Route: "fast"}, recordStream())
+	require.ErrorIs(t, err, errBadRequest)
+	openai.AssertNotCalled(t, "InvokeStream", mock.Anything, mock.Anything)
+}
+
+func TestContinuation(t *testing.T) {
+	chain := []hop{{target: Target{Provider: "openai"}}, {target: Target{Provider: "anthropic"}}}
+	req := &pb.LLMRequest{Provider: "openai", Messages: []*pb.ChatMessage{{Role: "user", Content: "Hi"}}}
+
+	// Anthropic continues a prefill directly, once its trailing whitespace is trimmed
+	continued := continuation(req, chain, 1, "Hello there, \n")
+	require.Len(t, continued.Messages, 2)
+	require.Equal(t, "assistant", continued.Messages[1].Role)
+	require.Equal(t, "Hello there,", continued.Messages[1].Content)
+	require.Len(t, req.Messages, 1)
+
+	// Nothing to continue leaves the request as it was
+	require.Len(t, continuation(req, chain, 1, "").Messages, 1)
+}
@@ -2,30 +2,340 @@ package server
 
 import (
 	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
 )
 
+// HealthConfig configures how provider health is judged
+type HealthConfig struct {
+	// ErrorRate is the share of failed requests within Window at which a provider is
+	// reported NOT_SERVING
+	ErrorRate float64
+
+	// MinRequests is the number of requests within Window needed before the error rate
+	// is trusted
+	MinRequests int
+
+	// Window is how far back request outcomes count
+	Window time.Duration
+
+	// ProbeInterval is how often each provider is sent a tiny request; 0 disables
+	// probes, leaving health to real traffic
+	ProbeInterval time.Duration
+
+	// ProbeTimeout bounds a probe
+	ProbeTimeout time.Duration
+
+	// CheckInterval is how often watched statuses are re-evaluated
+	CheckInterval time.Duration
+}
+
+// DefaultHealthConfig returns the default health settings, without probes
+func DefaultHealthConfig() HealthConfig {
+	return HealthConfig{
+		ErrorRate:     0.5,
+		MinRequests:   10,
+		Window:        time.Minute,
+		ProbeTimeout:  10 * time.Second,
+		CheckInterval: 5 * time.Second,
+	}
+}
+
 // healthServer implements the gRPC health check service
 type healthServer struct {
 	grpc_health_v1.UnimplementedHealthServer
+	config HealthConfig
+	// llm supplies the providers, their circuits and request outcomes; nil reports
+	// everything as serving
+	llm *LLMServer
+	// shuttingDown reports every service as not serving while the server drains
+	shuttingDown atomic.Bool
+	now          func() time.Time
+
+	mu sync.Mutex
+	// probeErrs holds the failure of each provider's last probe, if it failed
+	probeErrs map[string]error
+	// changed is closed and replaced whenever statuses may have changed
+	changed chan struct{}
+	stop    chan struct{}
 }
 
-// Check implements the gRPC health check service
+// Check implements the gRPC health check service. The empty service and
+// "llm.v1.LLMService" are the server process, which serves until it shuts down, so a
+// vendor incident fails requests fast with UNAVAILABLE rather than taking every
+// instance out of rotation; "llm.v1.LLMService/<provider>" and "llm.v1.LLMService/<provider>/<model>" report
+// NOT_SERVING while their circuit is open, their error rate is too high or their last
+// probe failed. The service prefix may be left out. Everything reports NOT_SERVING once
+// the server is shutting down.
 func (s *healthServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	servingStatus, err := s.status(req.Service)
+	if err != nil {
+		return nil, err
+	}
 	return &grpc_health_v1.HealthCheckResponse{
-		Status: grpc_health_v1.HealthCheckResponse_SERVING,
+		Status: servingStatus,
 	}, nil
 }
 
-// Watch implements the gRPC health check service
+// Watch implements the gRPC health check service, sending the service's status and
+// then every change to it. Watches end once the server is shutting down, so they do
+// not hold up the drain.
 func (s *healthServer) Watch(req *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
-	return stream.Send(&grpc_health_v1.HealthCheckResponse{
-		Status: grpc_health_v1.HealthCheckResponse_SERVING,
-	})
+	var last grpc_health_v1.HealthCheckResponse_ServingStatus
+	for first := true; ; first = false {
+		s.mu.Lock()
+		changed := s.changed
+		s.mu.Unlock()
+
+		// Unknown services are watched too, in case they appear
+		servingStatus, _ := s.status(req.Service)
+		if first || servingStatus != last {
+			if err := stream.Send(&grpc_health_v1.HealthCheckResponse{Status: servingStatus}); err != nil {
+				return err
+			}
+			last = servingStatus
+		}
+		if s.shuttingDown.Load() {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// status returns the serving status of a service
+func (s *healthServer) status(service string) (grpc_health_v1.HealthCheckResponse_ServingStatus, error) {
+	if s.shuttingDown.Load() {
+		return grpc_health_v1.HealthCheckResponse_NOT_SERVING, nil
+	}
+
+	serviceName := pb.LLMService_ServiceDesc.ServiceName
+	service = strings.TrimPrefix(strings.TrimPrefix(service, serviceName), "/")
+	if service == "" || s.llm == nil {
+		return grpc_health_v1.HealthCheckResponse_SERVING, nil
+	}
+
+	key := circuitKey{provider: service}
+	if i := strings.Index(service, "/"); i >= 0 {
+		key = circuitKey{provider: service[:i], model: service[i+1:]}
+	}
+	if _, ok := s.llm.providers[key.provider]; !ok {
+		return grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN, status.Errorf(codes.NotFound, "unknown service: %s", service)
+	}
+
+	if !s.providerServing(key.provider) || s.circuitOpen(key) {
+		return grpc_health_v1.HealthCheckResponse_NOT_SERVING, nil
+	}
+	return grpc_health_v1.HealthCheckResponse_SERVING, nil
+}
+
+// providerServing reports whether a provider's circuit is closed, its recent error
+// rate is acceptable and its last probe, if any, succeeded
+func (s *healthServer) providerServing(name string) bool {
+	if s.circuitOpen(circuitKey{provider: name}) {
+		return false
+	}
+	failed, total := s.llm.outcomes.rate(name, s.now().Add(-s.config.Window))
+	if total >= s.config.MinRequests && total > 0 && float64(failed)/float64(total) >= s.config.ErrorRate {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.probeErrs[name] == nil
+}
+
+// circuitOpen reports whether a provider or model circuit is open
+func (s *healthServer) circuitOpen(key circuitKey) bool {
+	if s.llm.circuits == nil {
+		return false
+	}
+	state, ok := s.llm.circuits.state(key)
+	return ok && state == pb.CircuitState_CIRCUIT_STATE_OPEN
 }
 
 // NewHealthServer creates a new health check server
 func NewHealthServer() *healthServer {
-	return &healthServer{}
+	return &healthServer{
+		config:    DefaultHealthConfig(),
+		now:       time.Now,
+		probeErrs: make(map[string]error),
+		changed:   make(chan struct{}),
+		stop:      make(chan struct{}),
+	}
+}
+
+// WithLLMServer reports the health of the server's providers and models
+func (s *healthServer) WithLLMServer(llm *LLMServer) *healthServer {
+	s.llm = llm
+	return s
+}
+
+// WithConfig sets how provider health is judged
+func (s *healthServer) WithConfig(config HealthConfig) *healthServer {
+	s.config = config
+	return s
+}
+
+// Start re-evaluates watched statuses every CheckInterval, and probes the providers if
+// probes are enabled, until Shutdown
+func (s *healthServer) Start() {
+	if s.config.CheckInterval > 0 {
+		go s.run(s.config.CheckInterval, s.notify)
+	}
+	if s.config.ProbeInterval > 0 && s.llm != nil {
+		go s.run(s.config.ProbeInterval, s.probe)
+	}
+}
+
+// run calls f every interval until the server shuts down
+func (s *healthServer) run(interval time.Duration, f func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			f()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// notify wakes the watchers to re-evaluate their statuses
+func (s *healthServer) notify() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// probeMaxTokens is the output budget of a probe, the smallest every provider accepts
+// (OpenAI's Responses API rejects fewer than 16)
+const probeMaxTokens = 16
+
+// probe sends every provider a tiny request and records the outcomes. Probes are
+// judged like real traffic, so only errors that count against a circuit, such as
+// overload or timeouts, mark a provider unhealthy; a rejected request does not.
+//
+// A probe is a single attempt sent to the provider beneath its wrappers, with the first
+// key of a key pool, so it spends no time on retries and a rate-limited probe does not
+// rest a key real traffic uses. Probes also bypass the circuits and concurrency limits,
+// and their outcomes do not count towards the error rate.
+func (s *healthServer) probe() {
+	var wg sync.WaitGroup
+	for name, wrapped := range s.llm.providers {
+		p := provider.Unwrap(wrapped)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), s.config.ProbeTimeout)
+			defer cancel()
+			_, err := p.Invoke(ctx, &pb.LLMRequest{
+				Provider:  name,
+				Messages:  []*pb.ChatMessage{{Role: "user", Content: "ping"}},
+				MaxTokens: probeMaxTokens,
+			})
+			if classify(err) != outcomeFailure {
+				err = nil
+			}
+
+			s.mu.Lock()
+			previous := s.probeErrs[name]
+			s.probeErrs[name] = err
+			s.mu.Unlock()
+			if err != nil && previous == nil {
+				zap.L().Warn("health probe failed", zap.String("provider", name), zap.Error(err))
+			}
+		}()
+	}
+	wg.Wait()
+	s.notify()
+}
+
+// Shutdown reports every service as NOT_SERVING, so load balancers stop sending
+// requests while the server drains, and ends the watches and probes
+func (s *healthServer) Shutdown() {
+	if s.shuttingDown.Swap(true) {
+		return
+	}
+	close(s.stop)
+	s.notify()
+}
+
+// outcomeWindowSize is the number of recent outcomes kept per provider
+const outcomeWindowSize = 256
+
+// outcomeSample is the outcome of one request to a provider
+type outcomeSample struct {
+	at     time.Time
+	failed bool
+}
+
+// outcomes keeps the most recent request outcomes of each provider for its error rate
+type outcomes struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	samples map[string][]outcomeSample
+	next    map[string]int
+}
+
+func newOutcomes() *outcomes {
+	return &outcomes{
+		now:     time.Now,
+		samples: make(map[string][]outcomeSample),
+		next:    make(map[string]int),
+	}
+}
+
+// record adds the outcome of a request to a provider. Requests that ended without
+// saying anything about the provider, such as canceled ones, are not counted.
+func (o *outcomes) record(providerName string, err error) {
+	result := classify(err)
+	if result == outcomeIgnored {
+		return
+	}
+	sample := outcomeSample{at: o.now(), failed: result == outcomeFailure}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	samples := o.samples[providerName]
+	if len(samples) < outcomeWindowSize {
+		o.samples[providerName] = append(samples, sample)
+		return
+	}
+	samples[o.next[providerName]] = sample
+	o.next[providerName] = (o.next[providerName] + 1) % outcomeWindowSize
+}
+
+// rate returns the number of failed and all recorded requests to a provider since a time
+func (o *outcomes) rate(providerName string, since time.Time) (failed, total int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, sample := range o.samples[providerName] {
+		if sample.at.After(since) {
+			total++
+			if sample.failed {
+				failed++
+			}
+		}
+	}
+	return failed, total
 }
@@ -0,0 +1,188 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	"github.com/c0rtexR/llm_service/internal/provider/retry"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+// healthWatch passes the statuses a Watch sends to a channel
+type healthWatch struct {
+	mockStream
+	statuses chan grpc_health_v1.HealthCheckResponse_ServingStatus
+}
+
+func (w *healthWatch) Send(resp *grpc_health_v1.HealthCheckResponse) error {
+	w.statuses <- resp.Status
+	return nil
+}
+
+// requireStatus waits for the next status a Watch sends
+func requireStatus(t *testing.T, w *healthWatch, want grpc_health_v1.HealthCheckResponse_ServingStatus) {
+	t.Helper()
+	select {
+	case got := <-w.statuses:
+		require.Equal(t, want, got)
+	case <-time.After(time.Second):
+		t.Fatalf("no %s status sent", want)
+	}
+}
+
+func newHealthTestServer() (*healthServer, *mockProvider, *mockProvider) {
+	anthropic, openai := &mockProvider{}, &mockProvider{}
+	s := New(map[string]provider.LLMProvider{"anthropic": anthropic, "openai": openai})
+	config := DefaultHealthConfig()
+	config.MinRequests = 4
+	return NewHealthServer().WithLLMServer(s).WithConfig(config), anthropic, openai
+}
+
+func checkStatus(t *testing.T, health *healthServer, service string) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	resp, err := health.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: service})
+	require.NoError(t, err)
+	return resp.Status
+}
+
+func TestHealthServer_ErrorRate(t *testing.T) {
+	health, anthropic, _ := newHealthTestServer()
+	anthropic.On("Invoke", mock.Anything, mock.Anything).Return(nil, errOverloaded).Times(3)
+	anthropic.On("Invoke", mock.Anything, mock.Anything).Return(&pb.LLMResponse{Content: "hi"}, nil)
+
+	invoke := func() {
+		_, _ = health.llm.Invoke(context.Background(), &pb.LLMRequest{Provider: "anthropic"})
+	}
+
+	// Too few requests to judge
+	for i := 0; i < 3; i++ {
+		invoke()
+	}
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, checkStatus(t, health, "llm.v1.LLMService/anthropic"))
+
+	invoke()
+	for _, service := range []string{"llm.v1.LLMService/anthropic", "anthropic", "llm.v1.LLMService/anthropic/claude-3-5-haiku-latest"} {
+		require.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, checkStatus(t, health, service), service)
+	}
+
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, checkStatus(t, health, "llm.v1.LLMService/openai"))
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, checkStatus(t, health, "llm.v1.LLMService"))
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, checkStatus(t, health, ""))
+
+	// Failures age out of the window
+	now := time.Now().Add(2 * time.Minute)
+	health.now = func() time.Time { return now }
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, checkStatus(t, health, "llm.v1.LLMService/anthropic"))
+}
+
+func TestHealthServer_ServerStatusIgnoresProviders(t *testing.T) {
+	health, anthropic, openai := newHealthTestServer()
+	anthropic.On("Invoke", mock.Anything, mock.Anything).Return(nil, errOverloaded)
+	openai.On("Invoke", mock.Anything, mock.Anything).Return(nil, errOverloaded)
+	health.probe()
+
+	// With every provider down the server still serves, failing requests fast
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, checkStatus(t, health, "llm.v1.LLMService/anthropic"))
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, checkStatus(t, health, "llm.v1.LLMService/openai"))
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, checkStatus(t, health, "llm.v1.LLMService"))
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, checkStatus(t, health, ""))
+}
+
+func TestHealthServer_IgnoresCanceledRequests(t *testing.T) {
+	health, anthropic, _ := newHealthTestServer()
+	anthropic.On("Invoke", mock.Anything, mock.Anything).Return(nil, context.Canceled)
+
+	for i := 0; i < 5; i++ {
+		_, _ = health.llm.Invoke(context.Background(), &pb.LLMRequest{Provider: "anthropic"})
+	}
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, checkStatus(t, health, "llm.v1.LLMService/anthropic"))
+}
+
+func TestHealthServer_Probes(t *testing.T) {
+	health, anthropic, openai := newHealthTestServer()
+	anthropic.On("Invoke", mock.Anything, mock.Anything).Return(nil, errOverloaded).Once()
+	anthropic.On("Invoke", mock.Anything, mock.Anything).Return(&pb.LLMResponse{}, nil)
+	openai.On("Invoke", mock.Anything, mock.Anything).Return(nil, errBadRequest)
+
+	// An overloaded provider is unhealthy, while one that rejects the probe itself is not
+	health.probe()
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, checkStatus(t, health, "llm.v1.LLMService/anthropic"))
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, checkStatus(t, health, "llm.v1.LLMService/openai"))
+
+	// Probes send a small request every provider accepts
+	req := anthropic.Calls[0].Arguments.Get(1).(*pb.LLMRequest)
+	require.Equal(t, int32(probeMaxTokens), req.MaxTokens)
+
+	health.probe()
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, checkStatus(t, health, "llm.v1.LLMService/anthropic"))
+}
+
+func TestHealthServer_ProbesBypassRetries(t *testing.T) {
+	m := &mockProvider{}
+	m.On("Invoke", mock.Anything, mock.Anything).Return(nil, errOverloaded)
+	s := New(map[string]provider.LLMProvider{"anthropic": retry.New("anthropic", m, retry.DefaultPolicy())})
+	health := NewHealthServer().WithLLMServer(s)
+
+	// One failed attempt is enough, without waiting out backoffs
+	health.probe()
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, checkStatus(t, health, "llm.v1.LLMService/anthropic"))
+	m.AssertNumberOfCalls(t, "Invoke", 1)
+}
+
+func TestHealthServer_Watch(t *testing.T) {
+	health, anthropic, openai := newHealthTestServer()
+	anthropic.On("Invoke", mock.Anything, mock.Anything).Return(nil, errOverloaded).Once()
+	anthropic.On("Invoke", mock.Anything, mock.Anything).Return(&pb.LLMResponse{}, nil)
+	openai.On("Invoke", mock.Anything, mock.Anything).Return(&pb.LLMResponse{}, nil)
+
+	stream := &healthWatch{
+		mockStream: mockStream{ctx: context.Background()},
+		statuses:   make(chan grpc_health_v1.HealthCheckResponse_ServingStatus, 1),
+	}
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- health.Watch(&grpc_health_v1.HealthCheckRequest{Service: "llm.v1.LLMService/anthropic"}, stream)
+	}()
+	requireStatus(t, stream, grpc_health_v1.HealthCheckResponse_SERVING)
+
+	// Transitions are pushed; re-evaluations without a change are not
+	health.probe()
+	requireStatus(t, stream, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	health.notify()
+	health.probe()
+	requireStatus(t, stream, grpc_health_v1.HealthCheckResponse_SERVING)
+
+	// Watches end when the server shuts down, so they do not hold up the drain
+	health.Shutdown()
+	requireStatus(t, stream, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	select {
+	case err := <-watchErr:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("watch did not end on shutdown")
+	}
+}
+
+func TestHealthServer_WatchUnknownService(t *testing.T) {
+	health, _, _ := newHealthTestServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &healthWatch{
+		mockStream: mockStream{ctx: ctx},
+		statuses:   make(chan grpc_health_v1.HealthCheckResponse_ServingStatus, 1),
+	}
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- health.Watch(&grpc_health_v1.HealthCheckRequest{Service: "llm.v1.LLMService/unknown"}, stream)
+	}()
+
+	// Unknown services are reported rather than ending the watch
+	requireStatus(t, stream, grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN)
+	cancel()
+	require.ErrorIs(t, <-watchErr, context.Canceled)
+}
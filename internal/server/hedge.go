@@ -0,0 +1,366 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+// HedgePolicy configures hedged requests. When the primary hop has not produced a first
+// token within the hedging delay, a duplicate request goes to a secondary and whichever
+// answers first is used; the other is canceled.
+type HedgePolicy struct {
+	// Delay is how long the primary may go without a first token before the hedge is sent
+	Delay time.Duration
+
+	// Percentile, if set, replaces Delay with that percentile of the target's observed
+	// time to first token (e.g., 0.95) once MinSamples have been observed
+	Percentile float64
+
+	// MinSamples is the number of observations needed before Percentile is used
+	MinSamples int
+}
+
+// DefaultHedgePolicy returns a policy that hedges at the observed p95 time to first
+// token, or after two seconds until enough requests have been seen
+func DefaultHedgePolicy() HedgePolicy {
+	return HedgePolicy{
+		Delay:      2 * time.Second,
+		Percentile: 0.95,
+		MinSamples: 20,
+	}
+}
+
+// WithHedging enables hedging for requests that ask for it
+func WithHedging(policy HedgePolicy) Option {
+	return func(s *LLMServer) {
+		s.hedging = newHedger(policy)
+	}
+}
+
+// latencyWindowSize is the number of recent latencies kept per target
+const latencyWindowSize = 200
+
+// latencyKey identifies a latency window; unary and streaming first tokens differ too
+// much to share one
+type latencyKey struct {
+	target Target
+	stream bool
+}
+
+// latencyWindow is a ring buffer of recent latencies
+type latencyWindow struct {
+	samples []time.Duration
+	next    int
+}
+
+func (w *latencyWindow) add(d time.Duration) {
+	if len(w.samples) < latencyWindowSize {
+		w.samples = append(w.samples, d)
+		return
+	}
+	w.samples[w.next] = d
+	w.next = (w.next + 1) % latencyWindowSize
+}
+
+func (w *latencyWindow) percentile(p float64) time.Duration {
+	sorted := slices.Clone(w.samples)
+	slices.Sort(sorted)
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[min(max(i, 0), len(sorted)-1)]
+}
+
+// hedger tracks time to first token per target and derives hedging delays from it
+type hedger struct {
+	policy HedgePolicy
+
+	mu      sync.Mutex
+	windows map[latencyKey]*latencyWindow
+}
+
+func newHedger(policy HedgePolicy) *hedger {
+	return &hedger{
+		policy:  policy,
+		windows: make(map[latencyKey]*latencyWindow),
+	}
+}
+
+// observe records a time to first token
+func (h *hedger) observe(key latencyKey, d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	w, ok := h.windows[key]
+	if !ok {
+		w = &latencyWindow{}
+		h.windows[key] = w
+	}
+	w.add(d)
+}
+
+// delay returns how long to wait for a first token before hedging
+func (h *hedger) delay(key latencyKey) time.Duration {
+	if h.policy.Percentile <= 0 {
+		return h.policy.Delay
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	w, ok := h.windows[key]
+	if !ok || len(w.samples) < max(h.policy.MinSamples, 1) {
+		return h.policy.Delay
+	}
+	return w.percentile(h.policy.Percentile)
+}
+
+// observe records a time to first token if hedging is enabled
+func (s *LLMServer) observe(key latencyKey, d time.Duration) {
+	if s.hedging != nil {
+		s.hedging.observe(key, d)
+	}
+}
+
+// hedges reports whether a request is hedged
+func (s *LLMServer) hedges(req *pb.LLMRequest) bool {
+	return s.hedging != nil && req.Hedge
+}
+
+// hedgeHop returns the hop that receives the hedge: the first fallback, or the primary
+// again when there is none
+func hedgeHop(chain []hop) int {
+	return min(1, len(chain)-1)
+}
+
+// logHedge records that a hedge request was sent
+func logHedge(chain []hop, n int, delay time.Duration) {
+	zap.L().Info("hedging slow request",
+		zap.Stringer("primary", chain[0].target),
+		zap.Stringer("hedge", chain[n].target),
+		zap.Duration("delay", delay))
+}
+
+// invokeHedged races the primary hop against a hedge sent after the hedging delay. It
+// returns the first successful response, the hop that served it, and the last hop
+// tried, so the fallback chain can continue after it if both fail.
+func (s *LLMServer) invokeHedged(ctx context.Context, req *pb.LLMRequest, chain []hop) (*pb.LLMResponse, *pb.ServedBy, int, error) {
+	// Canceling on return stops the slower attempt
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		resp   *pb.LLMResponse
+		n      int
+		hedged bool
+		err    error
+	}
+	results := make(chan result, 2)
+	attempt := func(n int, hedged bool) {
+		go func() {
+			resp, err := s.invokeHop(ctx, chain[n], hopRequest(req, chain, n))
+			results <- result{resp: resp, n: n, hedged: hedged, err: err}
+		}()
+	}
+
+	secondary := hedgeHop(chain)
+	delay := s.hedging.delay(latencyKey{target: chain[0].target})
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	attempt(0, false)
+	last, pending := 0, 1
+	for {
+		select {
+		case <-timer.C:
+			logHedge(chain, secondary, delay)
+			attempt(secondary, true)
+			last = secondary
+			pending++
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				served := servedBy(chain, r.n)
+				served.Hedged = r.hedged
+				return r.resp, served, last, nil
+			}
+			if pending == 0 {
+				return nil, nil, last, r.err
+			}
+		}
+	}
+}
+
+// streamAttempt is one of the provider streams raced by a hedged stream
+type streamAttempt struct {
+	n      int
+	hedged bool
+	start  time.Time
+	// watch holds the attempt's context, canceled when it loses or times out
+	watch *streamWatch
+
+	// Set by the attempt's goroutine before it reports its first event
+	respChan <-chan *pb.LLMStreamResponse
+	errChan  <-chan error
+	// release is nil once the attempt is finished, or if it was never admitted
+	release func(error)
+}
+
+// finish stops the attempt and reports its outcome to the circuit breakers
+func (a *streamAttempt) finish(err error) {
+	a.watch.stop()
+	if a.release != nil {
+		a.release(err)
+		a.release = nil
+	}
+}
+
+// firstEvent is the first thing a stream attempt produced: a chunk, an error, or
+// neither if the stream ended empty. A failed attempt carries its outcome for the
+// circuit breakers as well as the error for the client.
+type firstEvent struct {
+	attempt     *streamAttempt
+	resp        *pb.LLMStreamResponse
+	providerErr error
+	err         error
+}
+
+// runAttempt admits a stream attempt, opens its stream and reports its first event
+func (s *LLMServer) runAttempt(a *streamAttempt, p provider.LLMProvider, req *pb.LLMRequest, events chan<- firstEvent) {
+	ctx := a.watch.ctx
+	release, err := s.acquire(ctx, req)
+	if err != nil {
+		events <- firstEvent{attempt: a, err: err}
+		return
+	}
+	a.release = release
+	a.respChan, a.errChan = p.InvokeStream(ctx, req)
+
+	fail := func(err error) {
+		providerErr, err := a.watch.fail(err)
+		events <- firstEvent{attempt: a, providerErr: providerErr, err: err}
+	}
+	respChan, errChan := a.respChan, a.errChan
+	for {
+		select {
+		case resp, ok := <-respChan:
+			if !ok {
+				if errChan != nil {
+					if err := <-errChan; err != nil {
+						fail(err)
+						return
+					}
+				}
+				if ctx.Err() != nil {
+					fail(ctx.Err())
+					return
+				}
+				events <- firstEvent{attempt: a}
+				return
+			}
+			a.watch.chunk()
+			events <- firstEvent{attempt: a, resp: resp}
+			return
+		case err, ok := <-errChan:
+			if ok && err != nil {
+				fail(err)
+				return
+			}
+			if !ok {
+				errChan = nil
+			}
+		case <-ctx.Done():
+			fail(ctx.Err())
+			return
+		}
+	}
+}
+
+// discard finishes the next n attempts to report, which lost the race
+func discard(events <-chan firstEvent, n int) {
+	for ; n > 0; n-- {
+		(<-events).attempt.finish(context.Canceled)
+	}
+}
+
+// streamHedged races the primary hop's stream against a hedge sent if no chunk arrives
+// within the hedging delay, then forwards whichever produces a chunk first. It reports
+// the last hop tried.
+func (s *LLMServer) streamHedged(stream chunkSender, req *pb.LLMRequest, chain []hop, timeouts TimeoutPolicy) (int, error) {
+	events := make(chan firstEvent, 2)
+	var attempts []*streamAttempt
+	pending := 0
+
+	// stop cancels the attempts still running and frees their slots once they report
+	stop := func(winner *streamAttempt) {
+		for _, a := range attempts {
+			if a != winner {
+				a.watch.stop()
+			}
+		}
+		go discard(events, pending)
+		pending = 0
+	}
+	defer func() { stop(nil) }()
+
+	start := func(n int, hedged bool) {
+		a := &streamAttempt{n: n, hedged: hedged, start: time.Now(), watch: watchStream(stream.Context(), timeouts)}
+		attempts = append(attempts, a)
+		pending++
+		go s.runAttempt(a, chain[n].provider, hopRequest(req, chain, n), events)
+	}
+
+	secondary := hedgeHop(chain)
+	delay := s.hedging.delay(latencyKey{target: chain[0].target, stream: true})
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	start(0, false)
+	last := 0
+	for {
+		select {
+		case <-timer.C:
+			logHedge(chain, secondary, delay)
+			start(secondary, true)
+			last = secondary
+		case ev := <-events:
+			pending--
+			a := ev.attempt
+			if ev.err != nil {
+				a.finish(ev.providerErr)
+				if pending == 0 {
+					return last, ev.err
+				}
+				continue
+			}
+
+			// The first attempt to produce anything wins
+			stop(a)
+			if ev.resp == nil {
+				a.finish(nil)
+				return last, nil
+			}
+
+			s.observe(latencyKey{target: chain[a.n].target, stream: true}, time.Since(a.start))
+			served := servedBy(chain, a.n)
+			served.Hedged = a.hedged
+			ev.resp.ServedBy = served
+			if err := stream.Send(ev.resp); err != nil {
+				a.finish(context.Canceled)
+				return last, fmt.Errorf("failed to send response: %w", err)
+			}
+			providerErr, err := forward(stream, a.watch, a.respChan, a.errChan, nil, nil)
+			a.finish(providerErr)
+			return last, err
+		case <-stream.Context().Done():
+			return last, contextErr(stream.Context())
+		}
+	}
+}
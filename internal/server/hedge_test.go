@@ -0,0 +1,151 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+func TestHedgerDelay(t *testing.T) {
+	h := newHedger(HedgePolicy{Delay: time.Second, Percentile: 0.95, MinSamples: 20})
+	key := latencyKey{target: Target{Provider: "anthropic"}}
+
+	// The fixed delay applies until enough samples were seen
+	for i := 1; i <= 19; i++ {
+		h.observe(key, time.Duration(i)*time.Millisecond)
+	}
+	require.Equal(t, time.Second, h.delay(key))
+
+	for i := 20; i <= 100; i++ {
+		h.observe(key, time.Duration(i)*time.Millisecond)
+	}
+	require.Equal(t, 95*time.Millisecond, h.delay(key))
+
+	// Streams are tracked separately
+	require.Equal(t, time.Second, h.delay(latencyKey{target: key.target, stream: true}))
+
+	// The window keeps only the most recent samples
+	for i := 0; i < latencyWindowSize; i++ {
+		h.observe(key, 5*time.Millisecond)
+	}
+	require.Equal(t, 5*time.Millisecond, h.delay(key))
+
+	fixed := newHedger(HedgePolicy{Delay: 50 * time.Millisecond})
+	fixed.observe(key, time.Millisecond)
+	require.Equal(t, 50*time.Millisecond, fixed.delay(key))
+}
+
+// newHedgeTestServer returns a fallback test server that hedges after 10ms
+func newHedgeTestServer() (*LLMServer, *mockProvider, *mockProvider) {
+	return newFallbackTestServer(WithHedging(HedgePolicy{Delay: 10 * time.Millisecond}))
+}
+
+// blockUntilCanceled makes an Invoke mock wait for its context and report the cancellation
+func blockUntilCanceled(canceled chan<- struct{}) func(mock.Arguments) {
+	return func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+		close(canceled)
+	}
+}
+
+func TestLLMServer_InvokeHedged(t *testing.T) {
+	s, anthropic, openai := newHedgeTestServer()
+	canceled := make(chan struct{})
+	anthropic.On("Invoke", mock.Anything, mock.Anything).Run(blockUntilCanceled(canceled)).Return(nil, context.Canceled)
+	openai.On("Invoke", mock.Anything, mock.Anything).Return(&pb.LLMResponse{Content: "ok"}, nil)
+
+	resp, err := s.Invoke(context.Background(), &pb.LLMRequest{Route: "fast", Hedge: true})
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp.Content)
+	requireServedBy(t, resp.ServedBy, "openai", "gpt-4o-mini", 1)
+	require.True(t, resp.ServedBy.Hedged)
+
+	// The slower primary is canceled
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("primary request was not canceled")
+	}
+}
+
+func TestLLMServer_InvokeHedgedFastPrimary(t *testing.T) {
+	s, anthropic, openai := newHedgeTestServer()
+	anthropic.On("Invoke", mock.Anything, mock.Anything).Return(&pb.LLMResponse{Content: "fast"}, nil)
+
+	resp, err := s.Invoke(context.Background(), &pb.LLMRequest{Route: "fast", Hedge: true})
+	require.NoError(t, err)
+	require.Equal(t, "fast", resp.Content)
+	require.False(t, resp.ServedBy.Hedged)
+	openai.AssertNotCalled(t, "Invoke", mock.Anything, mock.Anything)
+}
+
+func TestLLMServer_InvokeHedgedFallsBackAfterBothFail(t *testing.T) {
+	s, anthropic, openai := newHedgeTestServer()
+	anthropic.On("Invoke", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		time.Sleep(20 * time.Millisecond)
+	}).Return(nil, errOverloaded)
+	openai.On("Invoke", mock.Anything, mock.MatchedBy(func(req *pb.LLMRequest) bool {
+		return req.Model == "gpt-4o-mini"
+	})).Return(nil, errOverloaded)
+	openai.On("Invoke", mock.Anything, mock.Anything).Return(&pb.LLMResponse{Content: "ok"}, nil)
+
+	// The hedge used the first fallback, so the chain continues with the second
+	resp, err := s.Invoke(context.Background(), &pb.LLMRequest{
+		Route:     "fast",
+		Hedge:     true,
+		Fallbacks: []*pb.FallbackTarget{{Provider: "openai", Model: "gpt-4o"}},
+	})
+	require.NoError(t, err)
+	requireServedBy(t, resp.ServedBy, "openai", "gpt-4o", 2)
+	require.False(t, resp.ServedBy.Hedged)
+}
+
+func TestLLMServer_InvokeNotHedgedWithoutOptIn(t *testing.T) {
+	s, anthropic, openai := newHedgeTestServer()
+	anthropic.On("Invoke", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		time.Sleep(30 * time.Millisecond)
+	}).Return(&pb.LLMResponse{Content: "slow"}, nil)
+
+	resp, err := s.Invoke(context.Background(), &pb.LLMRequest{Route: "fast"})
+	require.NoError(t, err)
+	require.Equal(t, "slow", resp.Content)
+	openai.AssertNotCalled(t, "Invoke", mock.Anything, mock.Anything)
+}
+
+func TestLLMServer_InvokeStreamHedged(t *testing.T) {
+	s, anthropic, openai := newHedgeTestServer()
+
+	// The primary stream produces nothing until it is canceled
+	canceled := make(chan struct{})
+	onStream(anthropic, fakeStream{stall: true}).Run(watchCanceled(canceled))
+	onStream(openai, fakeStream{chunks: []string{"hello", " world"}})
+
+	stream := recordStream()
+	require.NoError(t, s.InvokeStream(&pb.LLMRequest{Route: "fast", Hedge: true}, stream))
+	require.Equal(t, []string{"hello", " world"}, stream.content())
+	requireServedBy(t, stream.chunks[0].ServedBy, "openai", "gpt-4o-mini", 1)
+	require.True(t, stream.chunks[0].ServedBy.Hedged)
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("primary stream was not canceled")
+	}
+}
+
+func TestLLMServer_InvokeStreamHedgedFastPrimary(t *testing.T) {
+	s, anthropic, openai := newHedgeTestServer()
+	onStream(anthropic, fakeStream{chunks: []string{"fast"}})
+
+	stream := recordStream()
+	require.NoError(t, s.InvokeStream(&pb.LLMRequest{Route: "fast", Hedge: true}, stream))
+	require.Equal(t, []string{"fast"}, stream.content())
+	requireServedBy(t, stream.chunks[0].ServedBy, "anthropic", "claude-3-5-haiku-latest", 0)
+	require.False(t, stream.chunks[0].ServedBy.Hedged)
+	openai.AssertNotCalled(t, "InvokeStream", mock.Anything, mock.Anything)
+}
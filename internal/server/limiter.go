@@ -0,0 +1,344 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+// PriorityMetadataKey is the gRPC metadata key that sets a request's priority class:
+// "interactive", "default" or "batch"
+const PriorityMetadataKey = "x-priority"
+
+// Priority is the class a queued request waits in; lower values are admitted first
+type Priority int
+
+const (
+	// PriorityInteractive is for requests a user is waiting on
+	PriorityInteractive Priority = iota
+	// PriorityDefault is for requests that do not say
+	PriorityDefault
+	// PriorityBatch is for background work that can wait
+	PriorityBatch
+
+	numPriorities
+)
+
+var priorityNames = [numPriorities]string{"interactive", "default", "batch"}
+
+func (p Priority) String() string {
+	return priorityNames[p]
+}
+
+// priorityFromContext reads a request's priority class from its gRPC metadata
+func priorityFromContext(ctx context.Context) Priority {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return PriorityDefault
+	}
+	for _, value := range md.Get(PriorityMetadataKey) {
+		for p, name := range priorityNames {
+			if strings.EqualFold(strings.TrimSpace(value), name) {
+				return Priority(p)
+			}
+		}
+	}
+	return PriorityDefault
+}
+
+// LimitConfig caps the requests in flight to a provider or model. A model's requests
+// also count against its provider's limit.
+type LimitConfig struct {
+	// MaxInFlight is the number of requests sent to the provider at once
+	MaxInFlight int
+
+	// MaxQueue is the number of requests that may wait for a slot; more are rejected
+	// with ResourceExhausted
+	MaxQueue int
+
+	// MaxWait is how long a request waits for a slot before it is rejected; 0 waits
+	// until the request's own deadline
+	MaxWait time.Duration
+}
+
+// ParseLimits parses a comma-separated list of TARGET=MAX_IN_FLIGHT pairs, where a
+// target is a provider or a provider/model, e.g. "openai=20,anthropic/claude-3-5-sonnet-latest=5".
+// Every limit gets the given queue settings.
+func ParseLimits(spec string, maxQueue int, maxWait time.Duration) (map[string]LimitConfig, error) {
+	limits := make(map[string]LimitConfig)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		target, value, ok := strings.Cut(pair, "=")
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || err != nil || n < 1 || strings.TrimSpace(target) == "" {
+			return nil, fmt.Errorf("invalid limit %q: expected TARGET=MAX_IN_FLIGHT", pair)
+		}
+		limits[strings.TrimSpace(target)] = LimitConfig{MaxInFlight: n, MaxQueue: maxQueue, MaxWait: maxWait}
+	}
+	return limits, nil
+}
+
+// WithConcurrencyLimits caps the requests in flight per provider or model. Keys are a
+// provider name or provider/model; a model's own limit takes precedence over its
+// provider's, and providers without a limit are not capped.
+func WithConcurrencyLimits(limits map[string]LimitConfig) Option {
+	return func(s *LLMServer) {
+		s.limits = newLimits(limits)
+	}
+}
+
+// waiter is a request queued for a slot
+type waiter struct {
+	ready chan struct{}
+	// admitted is set, under the limiter's lock, when a slot is handed to the waiter
+	admitted bool
+}
+
+// classStats tracks the queue of one priority class
+type classStats struct {
+	admitted  int64
+	totalWait time.Duration
+	maxWait   time.Duration
+	rejected  int64
+	timedOut  int64
+}
+
+// limiter admits up to MaxInFlight requests and queues the rest by priority. A freed
+// slot goes straight to the oldest waiter of the most urgent class.
+type limiter struct {
+	config LimitConfig
+
+	mu       sync.Mutex
+	inFlight int
+	queues   [numPriorities][]*waiter
+	stats    [numPriorities]classStats
+}
+
+func newLimiter(config LimitConfig) *limiter {
+	return &limiter{config: config}
+}
+
+// queued returns the number of waiting requests; the caller must hold the lock
+func (l *limiter) queued() int {
+	n := 0
+	for _, q := range l.queues {
+		n += len(q)
+	}
+	return n
+}
+
+// acquire waits for a slot. It fails with ResourceExhausted if the queue is full or
+// the wait exceeds MaxWait, and with the context's error if the caller gives up.
+func (l *limiter) acquire(ctx context.Context, key string, priority Priority) (release func(), err error) {
+	l.mu.Lock()
+	if l.inFlight < l.config.MaxInFlight {
+		l.inFlight++
+		l.stats[priority].admitted++
+		l.mu.Unlock()
+		return l.release, nil
+	}
+	if l.queued() >= l.config.MaxQueue {
+		l.stats[priority].rejected++
+		l.mu.Unlock()
+		return nil, status.Errorf(codes.ResourceExhausted, "request queue for %s is full", key)
+	}
+	w := &waiter{ready: make(chan struct{})}
+	l.queues[priority] = append(l.queues[priority], w)
+	l.mu.Unlock()
+
+	start := time.Now()
+	var timeout <-chan time.Time
+	if l.config.MaxWait > 0 {
+		timer := time.NewTimer(l.config.MaxWait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-w.ready:
+	case <-timeout:
+	case <-ctx.Done():
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	waited := time.Since(start)
+	stats := &l.stats[priority]
+	if w.admitted {
+		// A slot may be handed over just as the wait ends; take it
+		stats.admitted++
+		stats.totalWait += waited
+		stats.maxWait = max(stats.maxWait, waited)
+		return l.release, nil
+	}
+
+	l.queues[priority] = removeWaiter(l.queues[priority], w)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	stats.timedOut++
+	return nil, status.Errorf(codes.ResourceExhausted, "waited %s in queue for %s", l.config.MaxWait, key)
+}
+
+// release frees a slot, handing it to the next waiter if there is one
+func (l *limiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for p, q := range l.queues {
+		if len(q) > 0 {
+			w := q[0]
+			l.queues[p] = q[1:]
+			w.admitted = true
+			close(w.ready)
+			return
+		}
+	}
+	l.inFlight--
+}
+
+// removeWaiter removes w from a queue
+func removeWaiter(queue []*waiter, w *waiter) []*waiter {
+	for i, other := range queue {
+		if other == w {
+			return append(queue[:i], queue[i+1:]...)
+		}
+	}
+	return queue
+}
+
+// info returns the limiter's current state
+func (l *limiter) info(key circuitKey) *pb.LimitInfo {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	info := &pb.LimitInfo{
+		Provider:    key.provider,
+		Model:       key.model,
+		MaxInFlight: int32(l.config.MaxInFlight),
+		InFlight:    int32(l.inFlight),
+		MaxQueue:    int32(l.config.MaxQueue),
+	}
+	for p, stats := range l.stats {
+		class := &pb.QueueClassInfo{
+			Priority:  Priority(p).String(),
+			Queued:    int32(len(l.queues[p])),
+			Admitted:  stats.admitted,
+			MaxWaitMs: stats.maxWait.Milliseconds(),
+			Rejected:  stats.rejected,
+			TimedOut:  stats.timedOut,
+		}
+		if stats.admitted > 0 {
+			class.AvgWaitMs = (stats.totalWait / time.Duration(stats.admitted)).Milliseconds()
+		}
+		info.Classes = append(info.Classes, class)
+	}
+	return info
+}
+
+// limits holds the concurrency limiters, created on first use
+type limits struct {
+	configs map[string]LimitConfig
+
+	mu       sync.Mutex
+	limiters map[circuitKey]*limiter
+}
+
+func newLimits(configs map[string]LimitConfig) *limits {
+	return &limits{
+		configs:  configs,
+		limiters: make(map[circuitKey]*limiter),
+	}
+}
+
+// limiter returns the limiter of a provider or provider/model, or nil if it is not
+// limited
+func (l *limits) limiter(key circuitKey) *limiter {
+	config, ok := l.configs[key.String()]
+	if !ok {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lim, ok := l.limiters[key]
+	if !ok {
+		lim = newLimiter(config)
+		l.limiters[key] = lim
+	}
+	return lim
+}
+
+// acquire waits for a slot to send a request to a provider and model: first under the
+// model's limit, if it has one, then under the provider's, so a model limit narrows
+// its provider's rather than adding to it
+func (l *limits) acquire(ctx context.Context, providerName, model string) (func(), error) {
+	priority := priorityFromContext(ctx)
+	releaseModel := func() {}
+	if model != "" {
+		key := circuitKey{provider: providerName, model: model}
+		if lim := l.limiter(key); lim != nil {
+			release, err := lim.acquire(ctx, key.String(), priority)
+			if err != nil {
+				return nil, err
+			}
+			releaseModel = release
+		}
+	}
+
+	key := circuitKey{provider: providerName}
+	lim := l.limiter(key)
+	if lim == nil {
+		return releaseModel, nil
+	}
+	releaseProvider, err := lim.acquire(ctx, key.String(), priority)
+	if err != nil {
+		releaseModel()
+		return nil, err
+	}
+	return func() {
+		releaseProvider()
+		releaseModel()
+	}, nil
+}
+
+// list returns every limiter that has been used, ordered by provider and model
+func (l *limits) list() []*pb.LimitInfo {
+	l.mu.Lock()
+	keys := make([]circuitKey, 0, len(l.limiters))
+	for key := range l.limiters {
+		keys = append(keys, key)
+	}
+	l.mu.Unlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].provider != keys[j].provider {
+			return keys[i].provider < keys[j].provider
+		}
+		return keys[i].model < keys[j].model
+	})
+
+	infos := make([]*pb.LimitInfo, len(keys))
+	for i, key := range keys {
+		l.mu.Lock()
+		lim := l.limiters[key]
+		l.mu.Unlock()
+		infos[i] = lim.info(key)
+	}
+	return infos
+}
@@ -0,0 +1,199 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits("openai=20, anthropic/claude-3-5-sonnet-latest=5,", 100, 30*time.Second)
+	require.NoError(t, err)
+	require.Equal(t, map[string]LimitConfig{
+		"openai":                             {MaxInFlight: 20, MaxQueue: 100, MaxWait: 30 * time.Second},
+		"anthropic/claude-3-5-sonnet-latest": {MaxInFlight: 5, MaxQueue: 100, MaxWait: 30 * time.Second},
+	}, limits)
+
+	for _, spec := range []string{"openai", "openai=0", "openai=many", "=5"} {
+		_, err := ParseLimits(spec, 0, 0)
+		require.ErrorContains(t, err, "expected TARGET=MAX_IN_FLIGHT", spec)
+	}
+}
+
+// withPriority returns an incoming gRPC context with the given priority header
+func withPriority(priority string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(PriorityMetadataKey, priority))
+}
+
+func TestPriorityFromContext(t *testing.T) {
+	require.Equal(t, PriorityDefault, priorityFromContext(context.Background()))
+	require.Equal(t, PriorityInteractive, priorityFromContext(withPriority("interactive")))
+	require.Equal(t, PriorityBatch, priorityFromContext(withPriority("Batch")))
+	require.Equal(t, PriorityDefault, priorityFromContext(withPriority("urgent")))
+}
+
+// queueRequest starts waiting for a slot and returns a channel with the result. An
+// admitted request holds its slot until hold is closed.
+func queueRequest(l *limiter, ctx context.Context, priority Priority, hold <-chan struct{}) <-chan error {
+	result := make(chan error, 1)
+	go func() {
+		release, err := l.acquire(ctx, "test", priority)
+		result <- err
+		if err == nil {
+			<-hold
+			release()
+		}
+	}()
+	return result
+}
+
+// waitQueued waits until the limiter has n queued requests
+func waitQueued(t *testing.T, l *limiter, n int) {
+	require.Eventually(t, func() bool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return l.queued() == n
+	}, time.Second, time.Millisecond)
+}
+
+func TestLimiterAdmitsByPriority(t *testing.T) {
+	l := newLimiter(LimitConfig{MaxInFlight: 1, MaxQueue: 10})
+	release, err := l.acquire(context.Background(), "test", PriorityDefault)
+	require.NoError(t, err)
+
+	// Batch work queued first still waits behind interactive requests
+	hold := make(chan struct{})
+	batch := queueRequest(l, context.Background(), PriorityBatch, hold)
+	waitQueued(t, l, 1)
+	interactive := queueRequest(l, context.Background(), PriorityInteractive, hold)
+	waitQueued(t, l, 2)
+
+	release()
+	require.NoError(t, <-interactive)
+	waitQueued(t, l, 1)
+	require.Equal(t, int32(1), l.info(circuitKey{}).Classes[PriorityBatch].Queued)
+
+	close(hold)
+	require.NoError(t, <-batch)
+	require.Eventually(t, func() bool {
+		return l.info(circuitKey{}).InFlight == 0
+	}, time.Second, time.Millisecond)
+
+	info := l.info(circuitKey{provider: "test"})
+	require.Equal(t, "interactive", info.Classes[0].Priority)
+	require.Equal(t, int64(1), info.Classes[PriorityInteractive].Admitted)
+	require.Equal(t, int64(1), info.Classes[PriorityBatch].Admitted)
+	require.Equal(t, int64(1), info.Classes[PriorityDefault].Admitted)
+}
+
+func TestLimiterRejectsWhenQueueIsFull(t *testing.T) {
+	l := newLimiter(LimitConfig{MaxInFlight: 1, MaxQueue: 1})
+	release, err := l.acquire(context.Background(), "test", PriorityDefault)
+	require.NoError(t, err)
+
+	hold := make(chan struct{})
+	close(hold)
+	queued := queueRequest(l, context.Background(), PriorityDefault, hold)
+	waitQueued(t, l, 1)
+
+	_, err = l.acquire(context.Background(), "test", PriorityInteractive)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	require.ErrorContains(t, err, "request queue for test is full")
+	require.Equal(t, int64(1), l.info(circuitKey{}).Classes[PriorityInteractive].Rejected)
+
+	release()
+	require.NoError(t, <-queued)
+}
+
+func TestLimiterMaxWait(t *testing.T) {
+	l := newLimiter(LimitConfig{MaxInFlight: 1, MaxQueue: 1, MaxWait: 20 * time.Millisecond})
+	release, err := l.acquire(context.Background(), "test", PriorityDefault)
+	require.NoError(t, err)
+	defer release()
+
+	_, err = l.acquire(context.Background(), "test", PriorityBatch)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// A caller that gives up leaves the queue without counting as a timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := queueRequest(l, ctx, PriorityBatch, nil)
+	waitQueued(t, l, 1)
+	cancel()
+	require.ErrorIs(t, <-canceled, context.Canceled)
+
+	info := l.info(circuitKey{})
+	require.Equal(t, int64(1), info.Classes[PriorityBatch].TimedOut)
+	require.Equal(t, int32(0), info.Classes[PriorityBatch].Queued)
+}
+
+func TestLLMServer_ConcurrencyLimits(t *testing.T) {
+	m := &mockProvider{}
+	started, block := make(chan struct{}), make(chan struct{})
+	m.On("Invoke", mock.Anything, mock.MatchedBy(func(req *pb.LLMRequest) bool {
+		return req.Model == "slow"
+	})).Run(func(mock.Arguments) {
+		close(started)
+		<-block
+	}).Return(&pb.LLMResponse{Content: "slow"}, nil)
+	m.On("Invoke", mock.Anything, mock.Anything).Return(&pb.LLMResponse{Content: "ok"}, nil)
+
+	s := New(map[string]provider.LLMProvider{"test": m}, WithConcurrencyLimits(map[string]LimitConfig{
+		"test/slow": {MaxInFlight: 1},
+	}))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.Invoke(context.Background(), &pb.LLMRequest{Provider: "test", Model: "slow"})
+		done <- err
+	}()
+	<-started
+
+	// Without a queue the limited model rejects at once, while other models are not limited
+	_, err := s.Invoke(withPriority("interactive"), &pb.LLMRequest{Provider: "test", Model: "slow"})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	_, err = s.Invoke(context.Background(), &pb.LLMRequest{Provider: "test", Model: "fast"})
+	require.NoError(t, err)
+
+	resp, err := NewAdminServer(s).ListLimits(context.Background(), &pb.ListLimitsRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Limits, 1)
+	require.Equal(t, "slow", resp.Limits[0].Model)
+	require.Equal(t, int32(1), resp.Limits[0].InFlight)
+	require.Equal(t, int64(1), resp.Limits[0].Classes[PriorityInteractive].Rejected)
+
+	close(block)
+	require.NoError(t, <-done)
+}
+
+func TestLimits_ModelLimitNarrowsProviderLimit(t *testing.T) {
+	l := newLimits(map[string]LimitConfig{
+		"test":     {MaxInFlight: 1},
+		"test/big": {MaxInFlight: 5},
+	})
+
+	release, err := l.acquire(context.Background(), "test", "big")
+	require.NoError(t, err)
+
+	// The model has room, but its provider does not
+	_, err = l.acquire(context.Background(), "test", "big")
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	_, err = l.acquire(context.Background(), "test", "other")
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	require.Equal(t, 1, l.limiter(circuitKey{provider: "test", model: "big"}).inFlight)
+
+	release()
+	require.Zero(t, l.limiter(circuitKey{provider: "test", model: "big"}).inFlight)
+	require.Zero(t, l.limiter(circuitKey{provider: "test"}).inFlight)
+	release, err = l.acquire(context.Background(), "test", "big")
+	require.NoError(t, err)
+	release()
+}
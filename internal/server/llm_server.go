@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/c0rtexR/llm_service/internal/provider"
 	pb "github.com/c0rtexR/llm_service/proto"
@@ -12,53 +13,323 @@ import (
 type LLMServer struct {
 	pb.UnimplementedLLMServiceServer
 	providers map[string]provider.LLMProvider
+	// circuits guards providers and models; nil disables circuit breaking
+	circuits *circuits
+	// routes holds the named fallback chains from the server config
+	routes map[string][]Target
+	// hedging tracks first-token latencies for hedged requests; nil disables hedging
+	hedging *hedger
+	// limits caps concurrent requests per provider or model; nil disables the caps
+	limits *limits
+	// streams buffers streams so clients can resume them; nil disables resumption
+	streams *streamStore
+	// timeouts limits requests, unless they select a route listed in routeTimeouts
+	timeouts      TimeoutPolicy
+	routeTimeouts map[string]TimeoutPolicy
+	// outcomes holds recent request outcomes per provider for the health service
+	outcomes *outcomes
+
+	// lifetime ends when the server shuts down, canceling the requests in flight
+	lifetime context.Context
+	shutdown context.CancelFunc
+}
+
+// Option configures an LLMServer
+type Option func(*LLMServer)
+
+// WithCircuitBreakers enables circuit breaking with separate settings for the
+// provider-wide circuits and the per-model circuits
+func WithCircuitBreakers(providerConfig, modelConfig BreakerConfig) Option {
+	return func(s *LLMServer) {
+		s.circuits = newCircuits(providerConfig, modelConfig)
+	}
 }
 
 // New creates a new LLM server with the given providers
-func New(providers map[string]provider.LLMProvider) *LLMServer {
-	return &LLMServer{
+func New(providers map[string]provider.LLMProvider, opts ...Option) *LLMServer {
+	s := &LLMServer{
 		providers: providers,
+		timeouts:  DefaultTimeoutPolicy(),
+		outcomes:  newOutcomes(),
+	}
+	s.lifetime, s.shutdown = context.WithCancel(context.Background())
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
-// Invoke implements the unary LLM call
+// Invoke implements the unary LLM call. Hops of a fallback chain are tried in order
+// until one succeeds or fails with an error the next hop cannot fix, all within the
+// request's deadline.
 func (s *LLMServer) Invoke(ctx context.Context, req *pb.LLMRequest) (*pb.LLMResponse, error) {
-	p, err := s.getProvider(req.Provider)
+	chain, err := s.resolveChain(req)
 	if err != nil {
 		return nil, err
 	}
+	ctx, untrack := s.track(ctx)
+	defer untrack()
+	ctx, cancel := withDeadline(ctx, s.timeoutPolicy(req))
+	defer cancel()
+
+	for n := 0; n < len(chain); n++ {
+		var resp *pb.LLMResponse
+		served := servedBy(chain, n)
+		if n == 0 && s.hedges(req) {
+			// A hedge may have used the next hop, so the chain continues after it
+			resp, served, n, err = s.invokeHedged(ctx, req, chain)
+		} else {
+			resp, err = s.invokeHop(ctx, chain[n], hopRequest(req, chain, n))
+		}
+		if err == nil {
+			resp.ServedBy = served
+			return resp, nil
+		}
+		if n >= len(chain)-1 || !canFallBack(ctx, err) {
+			break
+		}
+		logFallback(chain, n, err)
+	}
+	if ctx.Err() != nil {
+		return nil, contextErr(ctx)
+	}
+	return nil, err
+}
+
+// invokeHop sends a request to one provider through its circuit breakers and
+// concurrency limit
+func (s *LLMServer) invokeHop(ctx context.Context, h hop, req *pb.LLMRequest) (*pb.LLMResponse, error) {
+	release, err := s.acquire(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := h.provider.Invoke(ctx, req)
+	if err != nil && ctx.Err() != nil {
+		// The request's own deadline or cancellation is not the provider's fault
+		release(context.Canceled)
+	} else {
+		release(err)
+	}
+	if err == nil {
+		s.observe(latencyKey{target: h.target}, time.Since(start))
+	}
+	return resp, err
+}
 
-	return p.Invoke(ctx, req)
+// chunkSender is where a generated stream goes: the client's gRPC stream, or the
+// buffer of a resumable stream
+type chunkSender interface {
+	Context() context.Context
+	Send(*pb.LLMStreamResponse) error
 }
 
-// InvokeStream implements the streaming LLM call
+// InvokeStream implements the streaming LLM call. With stream resumption enabled the
+// generation runs detached from the client, which follows its buffer and may
+// reconnect with ResumeStream.
 func (s *LLMServer) InvokeStream(req *pb.LLMRequest, stream pb.LLMService_InvokeStreamServer) error {
-	p, err := s.getProvider(req.Provider)
+	chain, err := s.resolveChain(req)
 	if err != nil {
 		return err
 	}
+	var buf *streamBuffer
+	if s.streams != nil {
+		buf = s.streams.create(stream.Context())
+	}
+	if buf == nil {
+		return s.generate(stream, req, chain)
+	}
+	go func() {
+		buf.close(s.generate(buf, req, chain))
+	}()
+	return buf.follow(stream, 0)
+}
 
-	respChan, errChan := p.InvokeStream(stream.Context(), req)
+// generate streams a request's chain to out within the request's timeouts. A hop that
+// fails before sending anything gives way to the next one as for Invoke; one that fails
+// mid-stream is continued by the next hop from the partial answer, after a failover marker.
+func (s *LLMServer) generate(stream chunkSender, req *pb.LLMRequest, chain []hop) error {
+	ctx, untrack := s.track(stream.Context())
+	defer untrack()
+	timeouts := s.timeoutPolicy(req)
+	limited := limitStream(ctx, stream, timeouts)
+	defer limited.stop()
+
+	out := &transcript{chunkSender: limited}
+	var err error
+	for n := 0; n < len(chain); n++ {
+		if n == 0 && s.hedges(req) {
+			n, err = s.streamHedged(out, req, chain, timeouts)
+		} else if out.sent == 0 {
+			err = s.streamHop(out, chain[n], hopRequest(req, chain, n), servedBy(chain, n), timeouts)
+		} else {
+			out.marker = &pb.LLMStreamResponse{Type: pb.ResponseType_TYPE_FAILOVER, ServedBy: servedBy(chain, n)}
+			err = s.streamHop(out, chain[n], continuation(req, chain, n, out.content.String()), nil, timeouts)
+		}
+		if err == nil || n >= len(chain)-1 || !canFallBack(out.Context(), err) {
+			break
+		}
+		if out.sent > 0 {
+			logFailover(chain, n, out.content.Len(), err)
+		} else {
+			logFallback(chain, n, err)
+		}
+	}
+	return err
+}
+
+// streamHop forwards one provider's stream to the client, marking the first chunk with
+// the serving hop unless served is nil
+func (s *LLMServer) streamHop(stream chunkSender, h hop, req *pb.LLMRequest, served *pb.ServedBy, timeouts TimeoutPolicy) error {
+	release, err := s.acquire(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	w := watchStream(stream.Context(), timeouts)
+	defer w.stop()
+	respChan, errChan := h.provider.InvokeStream(w.ctx, req)
+	providerErr, err := forward(stream, w, respChan, errChan, served, func() {
+		s.observe(latencyKey{target: h.target, stream: true}, time.Since(start))
+	})
+	release(providerErr)
+	return err
+}
+
+// forward copies a provider stream to the client, marking the first chunk with served
+// and calling onFirst when it arrives; either may be nil. The watch stops the copy if
+// the provider is too slow. It returns the provider's outcome for its circuit breakers
+// and the error for the client.
+func forward(stream chunkSender, w *streamWatch, respChan <-chan *pb.LLMStreamResponse, errChan <-chan error,
+	served *pb.ServedBy, onFirst func()) (providerErr, err error) {
+	// The provider's health is unknown unless its stream finishes
+	providerErr = context.Canceled
+	sent := false
 
 	// Forward response chunks to the gRPC stream
 	for {
 		select {
 		case resp, ok := <-respChan:
 			if !ok {
-				// Response channel closed, we're done
-				return nil
+				// Response channel closed, report any pending error
+				if errChan != nil {
+					if err, ok := <-errChan; ok && err != nil {
+						return w.fail(err)
+					}
+				}
+				if w.ctx.Err() != nil {
+					// The provider ended the stream because it was canceled
+					return w.fail(w.ctx.Err())
+				}
+				return nil, nil
+			}
+			w.chunk()
+			if !sent {
+				resp.ServedBy = served
+				if onFirst != nil {
+					onFirst()
+				}
 			}
 			if err := stream.Send(resp); err != nil {
-				return fmt.Errorf("failed to send response: %w", err)
+				return providerErr, fmt.Errorf("failed to send response: %w", err)
 			}
+			sent = true
 		case err, ok := <-errChan:
 			if ok && err != nil {
-				return fmt.Errorf("provider error: %w", err)
+				return w.fail(err)
 			}
 			if !ok {
-				// Error channel closed without error
+				// Error channel closed without error; keep draining responses
+				errChan = nil
+			}
+		case <-w.ctx.Done():
+			return w.fail(w.ctx.Err())
+		}
+	}
+}
+
+// acquire admits a request through its circuit breakers, then waits for a slot under
+// its concurrency limit, so requests to an open circuit fail without queueing. The
+// returned release must be called with the request's final error, which is also
+// recorded for the health service.
+func (s *LLMServer) acquire(ctx context.Context, req *pb.LLMRequest) (func(error), error) {
+	release := func(err error) {
+		s.outcomes.record(req.Provider, err)
+	}
+	if s.circuits != nil {
+		releaseCircuit, err := s.circuits.acquire(req.Provider, req.Model)
+		if err != nil {
+			return nil, err
+		}
+		release = func(err error) {
+			releaseCircuit(err)
+			s.outcomes.record(req.Provider, err)
+		}
+	}
+	if s.limits == nil {
+		return release, nil
+	}
+
+	done, err := s.limits.acquire(ctx, req.Provider, req.Model)
+	if err != nil {
+		// The request never reached the provider
+		release(context.Canceled)
+		if ctx.Err() != nil {
+			return nil, contextErr(ctx)
+		}
+		return nil, err
+	}
+	return func(err error) {
+		done()
+		release(err)
+	}, nil
+}
+
+// CreateBatch submits a set of requests for asynchronous batch processing
+func (s *LLMServer) CreateBatch(ctx context.Context, req *pb.CreateBatchRequest) (*pb.BatchJob, error) {
+	p, err := s.getBatchProvider(req.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.CreateBatch(ctx, req.Requests)
+}
+
+// GetBatch returns the current status of a batch
+func (s *LLMServer) GetBatch(ctx context.Context, req *pb.GetBatchRequest) (*pb.BatchJob, error) {
+	p, err := s.getBatchProvider(req.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.GetBatch(ctx, req.BatchId)
+}
+
+// StreamBatchResults streams the results of an ended batch
+func (s *LLMServer) StreamBatchResults(req *pb.GetBatchRequest, stream pb.LLMService_StreamBatchResultsServer) error {
+	p, err := s.getBatchProvider(req.Provider)
+	if err != nil {
+		return err
+	}
+
+	resultChan, errChan := p.BatchResults(stream.Context(), req.BatchId)
+
+	// Forward results to the gRPC stream
+	for {
+		select {
+		case result, ok := <-resultChan:
+			if !ok {
+				// Result channel closed, report any pending error
+				if err, ok := <-errChan; ok && err != nil {
+					return fmt.Errorf("provider error: %w", err)
+				}
 				return nil
 			}
+			if err := stream.Send(result); err != nil {
+				return fmt.Errorf("failed to send result: %w", err)
+			}
 		case <-stream.Context().Done():
 			return stream.Context().Err()
 		}
@@ -73,3 +344,16 @@ func (s *LLMServer) getProvider(name string) (provider.LLMProvider, error) {
 	}
 	return p, nil
 }
+
+// getBatchProvider returns the provider for the given name if it supports batches
+func (s *LLMServer) getBatchProvider(name string) (provider.BatchProvider, error) {
+	p, err := s.getProvider(name)
+	if err != nil {
+		return nil, err
+	}
+	bp, ok := provider.AsBatchProvider(p)
+	if !ok {
+		return nil, fmt.Errorf("provider %s does not support batches", name)
+	}
+	return bp, nil
+}
@@ -28,9 +28,65 @@ func (m *mockProvider) Invoke(ctx context.Context, req *pb.LLMRequest) (*pb.LLMR
 
 func (m *mockProvider) InvokeStream(ctx context.Context, req *pb.LLMRequest) (<-chan *pb.LLMStreamResponse, <-chan error) {
 	args := m.Called(ctx, req)
+	if open, ok := args.Get(0).(func(context.Context) (<-chan *pb.LLMStreamResponse, <-chan error)); ok {
+		return open(ctx)
+	}
 	return args.Get(0).(<-chan *pb.LLMStreamResponse), args.Get(1).(<-chan error)
 }
 
+// fakeStream is a provider stream for InvokeStream mocks. It sends its chunks, holding
+// the second one until gate is closed if gate is set, and then ends with err, or if
+// stall is set, waits like an unresponsive provider until its context ends.
+type fakeStream struct {
+	chunks []string
+	err    error
+	gate   <-chan struct{}
+	stall  bool
+}
+
+// open starts the stream for a request's context
+func (f fakeStream) open(ctx context.Context) (<-chan *pb.LLMStreamResponse, <-chan error) {
+	respChan := make(chan *pb.LLMStreamResponse)
+	errChan := make(chan error, 1)
+	go func() {
+		defer close(respChan)
+		defer close(errChan)
+		for i, chunk := range f.chunks {
+			if i == 1 && f.gate != nil {
+				<-f.gate
+			}
+			select {
+			case respChan <- &pb.LLMStreamResponse{Type: pb.ResponseType_TYPE_CONTENT, Content: chunk}:
+			case <-ctx.Done():
+				errChan <- ctx.Err()
+				return
+			}
+		}
+		if f.stall {
+			<-ctx.Done()
+			errChan <- ctx.Err()
+		} else if f.err != nil {
+			errChan <- f.err
+		}
+	}()
+	return respChan, errChan
+}
+
+// onStream makes m's InvokeStream calls each open a new copy of stream
+func onStream(m *mockProvider, stream fakeStream) *mock.Call {
+	return m.On("InvokeStream", mock.Anything, mock.Anything).Return(stream.open, nil)
+}
+
+// watchCanceled makes a mock report when its call's context ends, without blocking it
+func watchCanceled(canceled chan<- struct{}) func(mock.Arguments) {
+	return func(args mock.Arguments) {
+		go func() {
+			<-args.Get(0).(context.Context).Done()
+			close(canceled)
+		}()
+	}
+}
+
 // mockStream implements pb.LLMService_InvokeStreamServer for testing
 type mockStream struct {
 	mock.Mock
@@ -65,6 +121,36 @@ func (m *mockStream) RecvMsg(msg interface{}) error {
 	return nil
 }
 
+// clientStream is a client's stream that records the chunks it is sent, dropping the
+// connection after dropAfter chunks if it is set
+type clientStream struct {
+	mockStream
+	dropAfter int
+	chunks    []*pb.LLMStreamResponse
+}
+
+// recordStream returns a client stream that records every chunk
+func recordStream() *clientStream {
+	return &clientStream{mockStream: mockStream{ctx: context.Background()}}
+}
+
+func (c *clientStream) Send(resp *pb.LLMStreamResponse) error {
+	if c.dropAfter > 0 && len(c.chunks) == c.dropAfter {
+		return errors.New("connection reset")
+	}
+	c.chunks = append(c.chunks, resp)
+	return nil
+}
+
+// content returns the text of the chunks the client received
+func (c *clientStream) content() []string {
+	var content []string
+	for _, resp := range c.chunks {
+		content = append(content, resp.Content)
+	}
+	return content
+}
+
 func TestLLMServer_Invoke(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -194,8 +280,9 @@ func TestLLMServer_InvokeStream(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mock := &mockProvider{}
 			stream := &mockStream{ctx: context.Background()}
-			respChan := make(chan *pb.LLMStreamResponse)
-			errChan := make(chan error)
+			// Buffered so the mock can fill them before returning
+			respChan := make(chan *pb.LLMStreamResponse, 10)
+			errChan := make(chan error, 1)
 
 			tt.setupMock(mock, respChan, errChan)
 			tt.setupStream(stream)
@@ -219,3 +306,89 @@ func TestLLMServer_InvokeStream(t *testing.T) {
 		})
 	}
 }
+
+// mockBatchProvider implements the LLMProvider and BatchProvider interfaces for testing
+type mockBatchProvider struct {
+	mockProvider
+}
+
+func (m *mockBatchProvider) CreateBatch(ctx context.Context, items []*pb.BatchRequestItem) (*pb.BatchJob, error) {
+	args := m.Called(ctx, items)
+	if job := args.Get(0); job != nil {
+		return job.(*pb.BatchJob), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockBatchProvider) GetBatch(ctx context.Context, batchID string) (*pb.BatchJob, error) {
+	args := m.Called(ctx, batchID)
+	if job := args.Get(0); job != nil {
+		return job.(*pb.BatchJob), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockBatchProvider) BatchResults(ctx context.Context, batchID string) (<-chan *pb.BatchResult, <-chan error) {
+	args := m.Called(ctx, batchID)
+	return args.Get(0).(<-chan *pb.BatchResult), args.Get(1).(<-chan error)
+}
+
+// mockBatchStream implements pb.LLMService_StreamBatchResultsServer for testing
+type mockBatchStream struct {
+	mockStream
+	results []*pb.BatchResult
+}
+
+func (m *mockBatchStream) Send(result *pb.BatchResult) error {
+	m.results = append(m.results, result)
+	return nil
+}
+
+func TestLLMServer_Batches(t *testing.T) {
+	batchProvider := &mockBatchProvider{}
+	server := New(map[string]provider.LLMProvider{
+		"batch": batchProvider,
+		"plain": &mockProvider{},
+	})
+
+	items := []*pb.BatchRequestItem{{CustomId: "req-1", Request: &pb.LLMRequest{}}}
+	batchProvider.On("CreateBatch", mock.Anything, items).Return(&pb.BatchJob{
+		Id:     "batch-1",
+		Status: pb.BatchStatus_BATCH_STATUS_IN_PROGRESS,
+	}, nil)
+	batchProvider.On("GetBatch", mock.Anything, "batch-1").Return(&pb.BatchJob{
+		Id:     "batch-1",
+		Status: pb.BatchStatus_BATCH_STATUS_ENDED,
+	}, nil)
+
+	resultChan := make(chan *pb.BatchResult, 1)
+	errChan := make(chan error)
+	resultChan <- &pb.BatchResult{CustomId: "req-1", Status: "succeeded"}
+	close(resultChan)
+	close(errChan)
+	batchProvider.On("BatchResults", mock.Anything, "batch-1").
+		Return((<-chan *pb.BatchResult)(resultChan), (<-chan error)(errChan))
+
+	job, err := server.CreateBatch(context.Background(), &pb.CreateBatchRequest{Provider: "batch", Requests: items})
+	require.NoError(t, err)
+	require.Equal(t, "batch-1", job.Id)
+
+	job, err = server.GetBatch(context.Background(), &pb.GetBatchRequest{Provider: "batch", BatchId: "batch-1"})
+	require.NoError(t, err)
+	require.Equal(t, pb.BatchStatus_BATCH_STATUS_ENDED, job.Status)
+
+	stream := &mockBatchStream{mockStream: mockStream{ctx: context.Background()}}
+	err = server.StreamBatchResults(&pb.GetBatchRequest{Provider: "batch", BatchId: "batch-1"}, stream)
+	require.NoError(t, err)
+	require.Len(t, stream.results, 1)
+	require.Equal(t, "req-1", stream.results[0].CustomId)
+
+	// Providers without batch support are rejected
+	_, err = server.CreateBatch(context.Background(), &pb.CreateBatchRequest{Provider: "plain", Requests: items})
+	require.ErrorContains(t, err, "does not support batches")
+
+	_, err = server.GetBatch(context.Background(), &pb.GetBatchRequest{Provider: "unknown", BatchId: "batch-1"})
+	require.ErrorContains(t, err, "unsupported provider")
+
+	batchProvider.AssertExpectations(t)
+}
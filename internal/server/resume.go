@@ -0,0 +1,313 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+// WithStreamResumption gives every stream an ID and buffers its chunks until retention
+// after it ends, so a client that loses its connection can pick it up with
+// ResumeStream. Generations then run to completion without a client, unless nobody has
+// followed them for retention or they are stopped with CancelStream. While the buffers
+// hold maxBytes, ended streams are dropped early to make room, and new streams that
+// still do not fit are sent unbuffered.
+func WithStreamResumption(retention time.Duration, maxBytes int64) Option {
+	return func(s *LLMServer) {
+		s.streams = newStreamStore(retention, maxBytes)
+	}
+}
+
+var (
+	// errStreamEnded is returned to a generation that outlives its buffer
+	errStreamEnded = errors.New("stream already ended")
+
+	// errStreamCanceled ends a stream stopped with CancelStream
+	errStreamCanceled = status.Error(codes.Canceled, "stream canceled")
+)
+
+// streamBuffer holds the chunks of a resumable stream for its followers. It is the
+// chunkSender of the stream's generation.
+type streamBuffer struct {
+	id        string
+	store     *streamStore
+	retention time.Duration
+	now       func() time.Time
+
+	// ctx is the generation's context, detached from the client that started it
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+
+	mu      sync.Mutex
+	chunks  []*pb.LLMStreamResponse
+	size    int64
+	done    bool
+	err     error
+	endedAt time.Time
+	// changed is closed and replaced whenever a chunk is added or the stream ends
+	changed   chan struct{}
+	followers int
+	// abandon cancels the generation once it has gone unfollowed for retention
+	abandon *time.Timer
+}
+
+// Context returns the generation's context
+func (b *streamBuffer) Context() context.Context {
+	return b.ctx
+}
+
+// Send numbers a chunk and adds it to the buffer
+func (b *streamBuffer) Send(resp *pb.LLMStreamResponse) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.done {
+		return errStreamEnded
+	}
+	resp.StreamId = b.id
+	resp.Sequence = int64(len(b.chunks) + 1)
+	b.chunks = append(b.chunks, resp)
+	size := int64(proto.Size(resp))
+	b.size += size
+	b.store.bytes.Add(size)
+	b.notify()
+	return nil
+}
+
+// close ends the stream with the generation's outcome
+func (b *streamBuffer) close(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.done = true
+	b.err = err
+	b.endedAt = b.now()
+	if b.abandon != nil {
+		b.abandon.Stop()
+	}
+	b.cancel(nil)
+	b.notify()
+	time.AfterFunc(b.retention, func() { b.store.remove(b) })
+}
+
+// notify wakes the followers; the caller must hold the lock
+func (b *streamBuffer) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// ended reports whether the stream has ended
+func (b *streamBuffer) ended() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.done
+}
+
+// expired reports whether the stream ended more than retention ago
+func (b *streamBuffer) expired(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.done && now.Sub(b.endedAt) > b.retention
+}
+
+// follow sends the chunks after the given sequence to a client, then the rest of the
+// stream as it is generated. It returns the stream's error once every chunk is sent.
+func (b *streamBuffer) follow(stream chunkSender, after int64) error {
+	b.mu.Lock()
+	if after < 0 || after > int64(len(b.chunks)) {
+		b.mu.Unlock()
+		return status.Errorf(codes.OutOfRange, "stream %s has no chunk %d", b.id, after)
+	}
+	b.followers++
+	if b.abandon != nil {
+		b.abandon.Stop()
+	}
+	b.mu.Unlock()
+	defer b.unfollow()
+
+	next := after
+	for {
+		b.mu.Lock()
+		chunks := b.chunks[next:]
+		done, err, changed := b.done, b.err, b.changed
+		b.mu.Unlock()
+
+		for _, resp := range chunks {
+			if err := stream.Send(resp); err != nil {
+				return fmt.Errorf("failed to send response: %w", err)
+			}
+			next++
+		}
+		if len(chunks) > 0 {
+			continue
+		}
+		if done {
+			return err
+		}
+
+		select {
+		case <-changed:
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// unfollow detaches a client, starting the abandon timer if it was the last one
+func (b *streamBuffer) unfollow() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.followers--
+	if b.followers == 0 && !b.done {
+		b.abandon = time.AfterFunc(b.retention, func() { b.cancel(nil) })
+	}
+}
+
+// streamStore holds the buffers of resumable streams until they expire
+type streamStore struct {
+	retention time.Duration
+	maxBytes  int64
+	now       func() time.Time
+	// bytes is the size of every buffered chunk
+	bytes atomic.Int64
+
+	mu      sync.Mutex
+	streams map[string]*streamBuffer
+}
+
+func newStreamStore(retention time.Duration, maxBytes int64) *streamStore {
+	return &streamStore{
+		retention: retention,
+		maxBytes:  maxBytes,
+		now:       time.Now,
+		streams:   make(map[string]*streamBuffer),
+	}
+}
+
+// create starts the buffer of a new stream, or returns nil if the buffers are full of
+// streams still in progress. Its generation keeps the values of the client's context,
+// such as its metadata, but not its cancellation.
+func (st *streamStore) create(ctx context.Context) *streamBuffer {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	now := st.now()
+	var ended []*streamBuffer
+	for _, other := range st.streams {
+		if other.expired(now) {
+			st.drop(other)
+		} else if other.ended() {
+			ended = append(ended, other)
+		}
+	}
+	if st.maxBytes > 0 && st.bytes.Load() >= st.maxBytes {
+		// Make room by dropping the streams that ended longest ago
+		sort.Slice(ended, func(i, j int) bool { return ended[i].endedAt.Before(ended[j].endedAt) })
+		for _, other := range ended {
+			if st.bytes.Load() < st.maxBytes {
+				break
+			}
+			st.drop(other)
+		}
+		if st.bytes.Load() >= st.maxBytes {
+			return nil
+		}
+	}
+
+	ctx, cancel := context.WithCancelCause(context.WithoutCancel(ctx))
+	b := &streamBuffer{
+		id:        newStreamID(),
+		store:     st,
+		retention: st.retention,
+		now:       st.now,
+		ctx:       ctx,
+		cancel:    cancel,
+		changed:   make(chan struct{}),
+	}
+	st.streams[b.id] = b
+	return b
+}
+
+// get returns the buffer of a stream that has not expired
+func (st *streamStore) get(id string) (*streamBuffer, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	b, ok := st.streams[id]
+	if !ok {
+		return nil, false
+	}
+	if b.expired(st.now()) {
+		st.drop(b)
+		return nil, false
+	}
+	return b, true
+}
+
+// remove drops an expired stream's buffer once its retention has passed
+func (st *streamStore) remove(b *streamBuffer) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.drop(b)
+}
+
+// drop removes a stream's buffer, if it is still held; the caller must hold the lock
+func (st *streamStore) drop(b *streamBuffer) {
+	if st.streams[b.id] != b {
+		return
+	}
+	delete(st.streams, b.id)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	st.bytes.Add(-b.size)
+}
+
+// newStreamID returns a random stream ID
+func newStreamID() string {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		panic(fmt.Sprintf("failed to generate stream ID: %v", err))
+	}
+	return hex.EncodeToString(id[:])
+}
+
+// ResumeStream reconnects a client to a buffered stream, replaying the chunks after
+// its last sequence and then following the live remainder
+func (s *LLMServer) ResumeStream(req *pb.ResumeStreamRequest, stream pb.LLMService_ResumeStreamServer) error {
+	if s.streams == nil {
+		return status.Error(codes.FailedPrecondition, "stream resumption is disabled")
+	}
+	b, ok := s.streams.get(req.StreamId)
+	if !ok {
+		return status.Errorf(codes.NotFound, "unknown or expired stream: %s", req.StreamId)
+	}
+	return b.follow(stream, req.LastSequence)
+}
+
+// CancelStream stops the generation of a resumable stream. Its followers receive the
+// chunks sent so far and then CANCELED.
+func (s *LLMServer) CancelStream(ctx context.Context, req *pb.CancelStreamRequest) (*pb.CancelStreamResponse, error) {
+	if s.streams == nil {
+		return nil, status.Error(codes.FailedPrecondition, "stream resumption is disabled")
+	}
+	b, ok := s.streams.get(req.StreamId)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown or expired stream: %s", req.StreamId)
+	}
+	b.cancel(errStreamCanceled)
+	return &pb.CancelStreamResponse{}, nil
+}
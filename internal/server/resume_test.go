@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+func newResumeTestServer(m *mockProvider) *LLMServer {
+	return New(map[string]provider.LLMProvider{"test": m}, WithStreamResumption(time.Minute, 0))
+}
+
+func TestLLMServer_ResumeStream(t *testing.T) {
+	m := &mockProvider{}
+	gate := make(chan struct{})
+	onStream(m, fakeStream{chunks: []string{"Once", " upon", " a time"}, gate: gate})
+	s := newResumeTestServer(m)
+
+	// The client drops after the first chunk, while the generation carries on
+	first := &clientStream{mockStream: mockStream{ctx: context.Background()}, dropAfter: 1}
+	go func() {
+		<-time.After(10 * time.Millisecond)
+		close(gate)
+	}()
+	err := s.InvokeStream(&pb.LLMRequest{Provider: "test"}, first)
+	require.ErrorContains(t, err, "connection reset")
+	require.Equal(t, []string{"Once"}, first.content())
+	streamID := first.chunks[0].StreamId
+	require.NotEmpty(t, streamID)
+	require.Equal(t, int64(1), first.chunks[0].Sequence)
+
+	resumed := recordStream()
+	err = s.ResumeStream(&pb.ResumeStreamRequest{StreamId: streamID, LastSequence: 1}, resumed)
+	require.NoError(t, err)
+	require.Equal(t, []string{" upon", " a time"}, resumed.content())
+	for i, resp := range resumed.chunks {
+		require.Equal(t, streamID, resp.StreamId)
+		require.Equal(t, int64(i+2), resp.Sequence)
+	}
+
+	// The stream can be replayed from the start until it expires
+	replayed := recordStream()
+	require.NoError(t, s.ResumeStream(&pb.ResumeStreamRequest{StreamId: streamID}, replayed))
+	require.Equal(t, []string{"Once", " upon", " a time"}, replayed.content())
+	m.AssertNumberOfCalls(t, "InvokeStream", 1)
+}
+
+func TestLLMServer_ResumeStreamReportsError(t *testing.T) {
+	m := &mockProvider{}
+	onStream(m, fakeStream{err: errOverloaded})
+	s := newResumeTestServer(m)
+
+	// The generation's error reaches the client following the stream
+	stream := recordStream()
+	err := s.InvokeStream(&pb.LLMRequest{Provider: "test"}, stream)
+	require.ErrorIs(t, err, errOverloaded)
+	require.Empty(t, stream.chunks)
+}
+
+func TestLLMServer_ResumeStreamErrors(t *testing.T) {
+	m := &mockProvider{}
+	onStream(m, fakeStream{chunks: []string{"hello"}})
+	s := newResumeTestServer(m)
+	now := time.Now()
+	s.streams.now = func() time.Time { return now }
+
+	stream := recordStream()
+	require.NoError(t, s.InvokeStream(&pb.LLMRequest{Provider: "test"}, stream))
+	streamID := stream.chunks[0].StreamId
+
+	err := s.ResumeStream(&pb.ResumeStreamRequest{StreamId: streamID, LastSequence: 5}, stream)
+	require.Equal(t, codes.OutOfRange, status.Code(err))
+
+	err = s.ResumeStream(&pb.ResumeStreamRequest{StreamId: "missing"}, stream)
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// Streams expire once the retention window has passed since they ended
+	now = now.Add(2 * time.Minute)
+	err = s.ResumeStream(&pb.ResumeStreamRequest{StreamId: streamID}, stream)
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	err = New(nil).ResumeStream(&pb.ResumeStreamRequest{StreamId: streamID}, stream)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestLLMServer_AbandonedStreamIsCanceled(t *testing.T) {
+	m := &mockProvider{}
+	canceled := make(chan struct{})
+	onStream(m, fakeStream{chunks: []string{"hello"}, stall: true}).Run(watchCanceled(canceled))
+	s := New(map[string]provider.LLMProvider{"test": m}, WithStreamResumption(10*time.Millisecond, 0))
+
+	// The client is gone as soon as the stream starts
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stream := &clientStream{mockStream: mockStream{ctx: ctx}}
+	require.ErrorIs(t, s.InvokeStream(&pb.LLMRequest{Provider: "test"}, stream), context.Canceled)
+
+	// Nobody follows the stream, so the generation is canceled after the retention window
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("abandoned generation was not canceled")
+	}
+}
+
+func TestLLMServer_CancelStream(t *testing.T) {
+	m := &mockProvider{}
+	onStream(m, fakeStream{chunks: []string{"Once", " upon a time"}, stall: true})
+	s := newResumeTestServer(m)
+
+	// The client gives up after the first chunk, leaving the generation running
+	first := &clientStream{mockStream: mockStream{ctx: context.Background()}, dropAfter: 1}
+	require.ErrorContains(t, s.InvokeStream(&pb.LLMRequest{Provider: "test"}, first), "connection reset")
+	streamID := first.chunks[0].StreamId
+
+	_, err := s.CancelStream(context.Background(), &pb.CancelStreamRequest{StreamId: streamID})
+	require.NoError(t, err)
+	resumed := recordStream()
+	err = s.ResumeStream(&pb.ResumeStreamRequest{StreamId: streamID, LastSequence: 2}, resumed)
+	require.Equal(t, codes.Canceled, status.Code(err))
+	require.Empty(t, resumed.chunks)
+
+	_, err = s.CancelStream(context.Background(), &pb.CancelStreamRequest{StreamId: "missing"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = New(nil).CancelStream(context.Background(), &pb.CancelStreamRequest{StreamId: streamID})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestStreamStore_MaxBytes(t *testing.T) {
+	st := newStreamStore(time.Minute, 10)
+
+	// A live stream over the limit keeps its chunks, but new streams are not buffered
+	live := st.create(context.Background())
+	require.NoError(t, live.Send(&pb.LLMStreamResponse{Content: "a long first chunk"}))
+	require.Nil(t, st.create(context.Background()))
+
+	// Once it ends, it is dropped to make room
+	live.close(nil)
+	require.NotNil(t, st.create(context.Background()))
+	_, ok := st.get(live.id)
+	require.False(t, ok)
+	require.Zero(t, st.bytes.Load())
+}
+
+func TestStreamStore_RemovesExpiredStreams(t *testing.T) {
+	st := newStreamStore(10*time.Millisecond, 0)
+	b := st.create(context.Background())
+	require.NoError(t, b.Send(&pb.LLMStreamResponse{Content: "hello"}))
+	b.close(nil)
+
+	// Ended streams are removed after the retention window without waiting for new ones
+	require.Eventually(t, func() bool {
+		st.mu.Lock()
+		defer st.mu.Unlock()
+		return len(st.streams) == 0
+	}, time.Second, 5*time.Millisecond)
+	require.Zero(t, st.bytes.Load())
+}
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errShuttingDown ends the requests still running when the server shuts down
+var errShuttingDown = status.Error(codes.Unavailable, "server is shutting down; retry on another instance")
+
+// track ties a request's context to the server's lifetime, so Shutdown can cancel it.
+// The returned function must be called once the request ends.
+func (s *LLMServer) track(ctx context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	stop := context.AfterFunc(s.lifetime, func() {
+		cancel(errShuttingDown)
+	})
+	return ctx, func() {
+		stop()
+		cancel(nil)
+	}
+}
+
+// Shutdown cancels every request still in flight, including streams generating for
+// clients that have disconnected. Callers see UNAVAILABLE, which they may retry on
+// another instance. It is meant for the end of a graceful stop's grace period.
+func (s *LLMServer) Shutdown() {
+	s.shutdown()
+}
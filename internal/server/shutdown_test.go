@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+func TestLLMServer_Shutdown(t *testing.T) {
+	m := &mockProvider{}
+	started := make(chan struct{})
+	m.On("Invoke", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		close(started)
+		<-args.Get(0).(context.Context).Done()
+	}).Return(nil, context.Canceled)
+	onStream(m, fakeStream{chunks: []string{"Once upon a time"}, stall: true})
+	s := New(map[string]provider.LLMProvider{"test": m}, WithStreamResumption(time.Minute, 0))
+
+	invokeErr := make(chan error, 1)
+	go func() {
+		_, err := s.Invoke(context.Background(), &pb.LLMRequest{Provider: "test"})
+		invokeErr <- err
+	}()
+	streamErr := make(chan error, 1)
+	firstChunk := make(chan struct{})
+	stream := &mockStream{ctx: context.Background()}
+	stream.On("Send", mock.Anything).Run(func(mock.Arguments) { close(firstChunk) }).Return(nil).Once()
+	go func() {
+		streamErr <- s.InvokeStream(&pb.LLMRequest{Provider: "test"}, stream)
+	}()
+	<-started
+	<-firstChunk
+
+	// Requests still running are told to go elsewhere
+	s.Shutdown()
+	for _, err := range []error{<-invokeErr, <-streamErr} {
+		require.Equal(t, codes.Unavailable, status.Code(err))
+		require.ErrorContains(t, err, "server is shutting down")
+	}
+	stream.AssertNumberOfCalls(t, "Send", 1)
+}
+
+func TestHealthServer_Shutdown(t *testing.T) {
+	health := NewHealthServer().WithLLMServer(New(map[string]provider.LLMProvider{"test": &mockProvider{}}))
+	health.Shutdown()
+
+	for _, service := range []string{"", "test"} {
+		resp, err := health.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		require.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, resp.Status)
+	}
+}
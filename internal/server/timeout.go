@@ -0,0 +1,308 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+// TimeoutPolicy limits how long a request may take; zero fields are unlimited. The
+// first-token, idle and stream limits apply to streams only.
+type TimeoutPolicy struct {
+	// Deadline bounds the whole request, including queueing and fallbacks
+	Deadline time.Duration
+
+	// FirstToken bounds the wait for a provider's first chunk. A provider that misses it
+	// gives way to the next hop of the fallback chain.
+	FirstToken time.Duration
+
+	// Idle bounds the gap between chunks. A provider that stalls gives way to the next
+	// hop, which continues the partial answer.
+	Idle time.Duration
+
+	// MaxStream bounds a stream from its first chunk to its end
+	MaxStream time.Duration
+}
+
+// DefaultTimeoutPolicy returns limits that stop hung providers without cutting off
+// models that reason for minutes before answering. The deadline is all that bounds a
+// unary request.
+func DefaultTimeoutPolicy() TimeoutPolicy {
+	return TimeoutPolicy{
+		Deadline:   10 * time.Minute,
+		FirstToken: 5 * time.Minute,
+		Idle:       2 * time.Minute,
+	}
+}
+
+// ParseTimeouts parses a comma-separated list of NAME=DURATION pairs over base, where
+// the names are deadline, first_token, idle and max_stream, e.g. "deadline=10m,idle=30s".
+// A zero duration removes the limit.
+func ParseTimeouts(spec string, base TimeoutPolicy) (TimeoutPolicy, error) {
+	policy := base
+	fields := map[string]*time.Duration{
+		"deadline":    &policy.Deadline,
+		"first_token": &policy.FirstToken,
+		"idle":        &policy.Idle,
+		"max_stream":  &policy.MaxStream,
+	}
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(pair, "=")
+		field, ok := fields[strings.TrimSpace(name)]
+		if !ok {
+			return base, fmt.Errorf("unknown timeout %q: expected deadline, first_token, idle or max_stream", name)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || d < 0 {
+			return base, fmt.Errorf("invalid %s timeout %q", name, value)
+		}
+		*field = d
+	}
+	return policy, nil
+}
+
+// WithTimeouts sets the timeout policy of requests, and of requests that select a
+// route; a route's policy replaces the default rather than adding to it. Requests may
+// override either with LLMRequest.timeouts.
+func WithTimeouts(defaults TimeoutPolicy, routes map[string]TimeoutPolicy) Option {
+	return func(s *LLMServer) {
+		s.timeouts = defaults
+		s.routeTimeouts = routes
+	}
+}
+
+// timeoutPolicy returns the limits for a request: its route's or the server's, with the
+// fields the request sets taking precedence
+func (s *LLMServer) timeoutPolicy(req *pb.LLMRequest) TimeoutPolicy {
+	policy := s.timeouts
+	if route, ok := s.routeTimeouts[req.Route]; ok && req.Route != "" {
+		policy = route
+	}
+
+	override := func(field *time.Duration, ms int64) {
+		if ms > 0 {
+			*field = time.Duration(ms) * time.Millisecond
+		}
+	}
+	if t := req.Timeouts; t != nil {
+		override(&policy.Deadline, t.DeadlineMs)
+		override(&policy.FirstToken, t.FirstTokenMs)
+		override(&policy.Idle, t.IdleMs)
+		override(&policy.MaxStream, t.MaxStreamMs)
+	}
+	return policy
+}
+
+// timeoutKind names the limit a request ran into
+type timeoutKind int
+
+const (
+	timeoutDeadline timeoutKind = iota
+	timeoutFirstToken
+	timeoutIdle
+	timeoutMaxStream
+)
+
+// timeoutError reports a request stopped by one of its limits. It is a
+// DeadlineExceeded status for the client, with a message naming the limit.
+type timeoutError struct {
+	kind  timeoutKind
+	limit time.Duration
+}
+
+func (e *timeoutError) Error() string {
+	switch e.kind {
+	case timeoutFirstToken:
+		return fmt.Sprintf("no first token within %s", e.limit)
+	case timeoutIdle:
+		return fmt.Sprintf("stream idle for more than %s", e.limit)
+	case timeoutMaxStream:
+		return fmt.Sprintf("stream exceeded its maximum duration of %s", e.limit)
+	default:
+		return fmt.Sprintf("request exceeded its deadline of %s", e.limit)
+	}
+}
+
+// GRPCStatus returns the status sent to the client
+func (e *timeoutError) GRPCStatus() *status.Status {
+	return status.New(codes.DeadlineExceeded, e.Error())
+}
+
+// Unwrap lets timeouts count as deadline errors, e.g. for the circuit breakers
+func (e *timeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// hopTimeout reports whether the timeout is the fault of one provider, so that the
+// next hop may do better
+func (e *timeoutError) hopTimeout() bool {
+	return e.kind == timeoutFirstToken || e.kind == timeoutIdle
+}
+
+// timeoutCause returns the timeout that ended ctx, or nil if it is still running or
+// ended for another reason
+func timeoutCause(ctx context.Context) *timeoutError {
+	var timeout *timeoutError
+	if errors.As(context.Cause(ctx), &timeout) {
+		return timeout
+	}
+	return nil
+}
+
+// contextErr returns why ctx ended: the timeout, shutdown or CancelStream that stopped
+// the request, or the context's own error if the client gave up
+func contextErr(ctx context.Context) error {
+	if timeout := timeoutCause(ctx); timeout != nil {
+		return timeout
+	}
+	if cause := context.Cause(ctx); errors.Is(cause, errShuttingDown) || errors.Is(cause, errStreamCanceled) {
+		return cause
+	}
+	return ctx.Err()
+}
+
+// withDeadline bounds ctx by the policy's deadline
+func withDeadline(ctx context.Context, policy TimeoutPolicy) (context.Context, context.CancelFunc) {
+	if policy.Deadline <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeoutCause(ctx, policy.Deadline, &timeoutError{kind: timeoutDeadline, limit: policy.Deadline})
+}
+
+// limitedStream sends a stream's generation to the client within the deadline and,
+// once its first chunk is sent, the maximum stream duration
+type limitedStream struct {
+	chunkSender
+	ctx       context.Context
+	cancel    context.CancelCauseFunc
+	stopTimer context.CancelFunc
+	maxStream time.Duration
+
+	once sync.Once
+}
+
+// limitStream bounds a generation running in ctx that sends to stream
+func limitStream(ctx context.Context, stream chunkSender, policy TimeoutPolicy) *limitedStream {
+	ctx, stopTimer := withDeadline(ctx, policy)
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &limitedStream{
+		chunkSender: stream,
+		ctx:         ctx,
+		cancel:      cancel,
+		stopTimer:   stopTimer,
+		maxStream:   policy.MaxStream,
+	}
+}
+
+// Context returns the generation's context, which ends when a limit is hit
+func (l *limitedStream) Context() context.Context {
+	return l.ctx
+}
+
+func (l *limitedStream) Send(resp *pb.LLMStreamResponse) error {
+	if l.maxStream > 0 {
+		l.once.Do(func() {
+			timer := time.AfterFunc(l.maxStream, func() {
+				l.cancel(&timeoutError{kind: timeoutMaxStream, limit: l.maxStream})
+			})
+			context.AfterFunc(l.ctx, func() { timer.Stop() })
+		})
+	}
+	return l.chunkSender.Send(resp)
+}
+
+// stop releases the generation's timers
+func (l *limitedStream) stop() {
+	l.cancel(nil)
+	l.stopTimer()
+}
+
+// streamWatch cancels a provider stream that is slow to produce its first chunk or
+// stalls between chunks
+type streamWatch struct {
+	ctx        context.Context
+	cancel     context.CancelCauseFunc
+	firstToken time.Duration
+	idle       time.Duration
+
+	mu      sync.Mutex
+	timer   *time.Timer
+	started bool
+}
+
+func watchStream(ctx context.Context, policy TimeoutPolicy) *streamWatch {
+	ctx, cancel := context.WithCancelCause(ctx)
+	w := &streamWatch{ctx: ctx, cancel: cancel, firstToken: policy.FirstToken, idle: policy.Idle}
+	if w.firstToken > 0 {
+		w.timer = time.AfterFunc(w.firstToken, w.expire)
+	}
+	return w
+}
+
+// expire cancels the stream with the limit it missed
+func (w *streamWatch) expire() {
+	w.mu.Lock()
+	timeout := &timeoutError{kind: timeoutFirstToken, limit: w.firstToken}
+	if w.started {
+		timeout = &timeoutError{kind: timeoutIdle, limit: w.idle}
+	}
+	w.mu.Unlock()
+	w.cancel(timeout)
+}
+
+// chunk records that a chunk arrived, restarting the idle timer
+func (w *streamWatch) chunk() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.started = true
+	switch {
+	case w.idle <= 0:
+		if w.timer != nil {
+			w.timer.Stop()
+		}
+	case w.timer == nil:
+		w.timer = time.AfterFunc(w.idle, w.expire)
+	default:
+		w.timer.Reset(w.idle)
+	}
+}
+
+// stop cancels the stream's context and timer once it has ended
+func (w *streamWatch) stop() {
+	w.mu.Lock()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.mu.Unlock()
+	w.cancel(nil)
+}
+
+// fail returns the outcome of a stream that ended with err: for the circuit breakers, the
+// timeout if it was the provider's fault, and for the client, the timeout that stopped
+// the stream if there was one, or else the provider's error
+func (w *streamWatch) fail(err error) (providerErr, clientErr error) {
+	if timeout := timeoutCause(w.ctx); timeout != nil {
+		if timeout.hopTimeout() {
+			return timeout, timeout
+		}
+		return context.Canceled, timeout
+	}
+	if w.ctx.Err() != nil {
+		// The client is gone or the server is shutting down
+		return context.Canceled, contextErr(w.ctx)
+	}
+	return err, fmt.Errorf("provider error: %w", err)
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/c0rtexR/llm_service/internal/provider"
+	pb "github.com/c0rtexR/llm_service/proto"
+)
+
+func TestParseTimeouts(t *testing.T) {
+	policy, err := ParseTimeouts("deadline=10m, idle=30s,first_token=0", DefaultTimeoutPolicy())
+	require.NoError(t, err)
+	require.Equal(t, TimeoutPolicy{Deadline: 10 * time.Minute, Idle: 30 * time.Second}, policy)
+
+	_, err = ParseTimeouts("total=1m", TimeoutPolicy{})
+	require.ErrorContains(t, err, "unknown timeout")
+	_, err = ParseTimeouts("idle=soon", TimeoutPolicy{})
+	require.ErrorContains(t, err, "invalid idle timeout")
+}
+
+func TestTimeoutPolicy(t *testing.T) {
+	s := New(nil, WithTimeouts(TimeoutPolicy{Idle: time.Minute, MaxStream: time.Hour}, map[string]TimeoutPolicy{
+		"fast": {Idle: 5 * time.Second},
+	}))
+
+	require.Equal(t, TimeoutPolicy{Idle: time.Minute, MaxStream: time.Hour}, s.timeoutPolicy(&pb.LLMRequest{}))
+	require.Equal(t, TimeoutPolicy{Idle: 5 * time.Second}, s.timeoutPolicy(&pb.LLMRequest{Route: "fast"}))
+
+	// Requests override the fields they set
+	policy := s.timeoutPolicy(&pb.LLMRequest{Route: "fast", Timeouts: &pb.Timeouts{DeadlineMs: 1500}})
+	require.Equal(t, TimeoutPolicy{Deadline: 1500 * time.Millisecond, Idle: 5 * time.Second}, policy)
+}
+
+func TestLLMServer_FirstTokenTimeoutFallsBack(t *testing.T) {
+	s, anthropic, openai := newFallbackTestServer(WithTimeouts(TimeoutPolicy{FirstToken: 20 * time.Millisecond}, nil))
+	onStream(anthropic, fakeStream{stall: true})
+	onStream(openai, fakeStream{chunks: []string{"hello"}})
+
+	stream := recordStream()
+	require.NoError(t, s.InvokeStream(&pb.LLMRequest{Route: "fast"}, stream))
+	require.Len(t, stream.chunks, 1)
+	requireServedBy(t, stream.chunks[0].ServedBy, "openai", "gpt-4o-mini", 1)
+
+	// Without a fallback the client learns which limit was hit
+	stream = recordStream()
+	err := s.InvokeStream(&pb.LLMRequest{Provider: "anthropic"}, stream)
+	require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	require.ErrorContains(t, err, "no first token within 20ms")
+}
+
+func TestLLMServer_IdleTimeoutFailsOver(t *testing.T) {
+	s, anthropic, openai := newFallbackTestServer(WithTimeouts(TimeoutPolicy{Idle: 20 * time.Millisecond}, nil))
+	onStream(anthropic, fakeStream{chunks: []string{"The answer is "}, stall: true})
+	onStream(openai, fakeStream{chunks: []string{"42."}})
+
+	stream := recordStream()
+	require.NoError(t, s.InvokeStream(&pb.LLMRequest{Route: "fast"}, stream))
+	require.Len(t, stream.chunks, 3)
+	require.Equal(t, pb.ResponseType_TYPE_FAILOVER, stream.chunks[1].Type)
+	require.Equal(t, "42.", stream.chunks[2].Content)
+
+	stream = recordStream()
+	err := s.InvokeStream(&pb.LLMRequest{Provider: "anthropic"}, stream)
+	require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	require.ErrorContains(t, err, "stream idle for more than 20ms")
+}
+
+func TestLLMServer_MaxStreamDuration(t *testing.T) {
+	s, anthropic, openai := newFallbackTestServer()
+	onStream(anthropic, fakeStream{chunks: []string{"Once upon a time"}, stall: true})
+
+	// The stream's own limit does not give way to the next hop
+	stream := recordStream()
+	err := s.InvokeStream(&pb.LLMRequest{Route: "fast", Timeouts: &pb.Timeouts{MaxStreamMs: 20}}, stream)
+	require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	require.ErrorContains(t, err, "stream exceeded its maximum duration of 20ms")
+	require.Len(t, stream.chunks, 1)
+	openai.AssertNotCalled(t, "InvokeStream", mock.Anything, mock.Anything)
+}
+
+func TestLLMServer_InvokeDefaultDeadline(t *testing.T) {
+	m := &mockProvider{}
+	var deadline time.Time
+	var ok bool
+	m.On("Invoke", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		deadline, ok = args.Get(0).(context.Context).Deadline()
+	}).Return(&pb.LLMResponse{}, nil)
+	s := New(map[string]provider.LLMProvider{"test": m})
+
+	// Unary requests are bounded even when nothing configures timeouts
+	_, err := s.Invoke(context.Background(), &pb.LLMRequest{Provider: "test"})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.WithinDuration(t, time.Now().Add(DefaultTimeoutPolicy().Deadline), deadline, time.Minute)
+}
+
+func TestLLMServer_InvokeDeadline(t *testing.T) {
+	m := &mockProvider{}
+	canceled := make(chan struct{})
+	m.On("Invoke", mock.Anything, mock.Anything).Run(blockUntilCanceled(canceled)).Return(nil, context.DeadlineExceeded)
+	s := New(map[string]provider.LLMProvider{"test": m})
+
+	_, err := s.Invoke(context.Background(), &pb.LLMRequest{Provider: "test", Timeouts: &pb.Timeouts{DeadlineMs: 20}})
+	require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	require.ErrorContains(t, err, "request exceeded its deadline of 20ms")
+	<-canceled
+}
@@ -0,0 +1,193 @@
+// Package sse decodes Server-Sent Events streams as specified by the WHATWG HTML
+// standard (https://html.spec.whatwg.org/multipage/server-sent-events.html).
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxLineSize bounds a single line in the stream. Providers send whole
+// responses in one data line (e.g. OpenAI's response.completed), so it is far
+// above bufio.Scanner's 64KB default.
+const DefaultMaxLineSize = 16 * 1024 * 1024
+
+// Event is a dispatched server-sent event
+type Event struct {
+	// Type is the event name from the "event" field, or "message" if none was set
+	Type string
+
+	// Data is the event payload; multiple data lines are joined with "\n"
+	Data string
+
+	// ID is the last event ID seen on the stream, which persists across events
+	ID string
+
+	// Retry is the reconnection time from the most recent valid "retry" field
+	Retry time.Duration
+}
+
+// Decoder reads events from an SSE stream
+type Decoder struct {
+	scanner   *bufio.Scanner
+	started   bool
+	eventType string
+	data      strings.Builder
+	hasData   bool
+	lastID    string
+	retry     time.Duration
+}
+
+// NewDecoder returns a decoder that reads from r with DefaultMaxLineSize
+func NewDecoder(r io.Reader) *Decoder {
+	return NewDecoderSize(r, DefaultMaxLineSize)
+}
+
+// NewDecoderSize returns a decoder that reads from r and rejects lines longer than maxLineSize
+func NewDecoderSize(r io.Reader, maxLineSize int) *Decoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, min(64*1024, maxLineSize)), maxLineSize)
+	scanner.Split(scanLines)
+	return &Decoder{scanner: scanner}
+}
+
+// Next returns the next event. It returns io.EOF when the stream ends; per the spec,
+// an event that was not terminated by a blank line before the end is discarded.
+func (d *Decoder) Next() (*Event, error) {
+	for d.scanner.Scan() {
+		line := d.scanner.Bytes()
+
+		// A UTF-8 byte order mark at the start of the stream is ignored
+		if !d.started {
+			d.started = true
+			line = bytes.TrimPrefix(line, []byte("\xEF\xBB\xBF"))
+		}
+
+		if len(line) == 0 {
+			if event := d.dispatch(); event != nil {
+				return event, nil
+			}
+			continue
+		}
+
+		// Lines starting with a colon are comments (often used as keep-alives)
+		if line[0] == ':' {
+			continue
+		}
+
+		field, value := line, []byte(nil)
+		if i := bytes.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], line[i+1:]
+			value = bytes.TrimPrefix(value, []byte(" "))
+		}
+		d.processField(string(field), value)
+	}
+
+	if err := d.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("sse: line exceeds maximum size: %w", err)
+		}
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// processField applies a single field to the event being built
+func (d *Decoder) processField(field string, value []byte) {
+	switch field {
+	case "event":
+		d.eventType = string(value)
+	case "data":
+		d.data.Write(value)
+		d.data.WriteByte('\n')
+		d.hasData = true
+	case "id":
+		// IDs containing NULL are ignored
+		if bytes.IndexByte(value, 0) < 0 {
+			d.lastID = string(value)
+		}
+	case "retry":
+		if ms, ok := parseRetry(value); ok {
+			d.retry = time.Duration(ms) * time.Millisecond
+		}
+	default:
+		// Unknown fields are ignored
+	}
+}
+
+// dispatch completes the current event, returning nil if it has no data
+func (d *Decoder) dispatch() *Event {
+	eventType := d.eventType
+	d.eventType = ""
+
+	if !d.hasData {
+		d.data.Reset()
+		return nil
+	}
+
+	data := d.data.String()
+	d.data.Reset()
+	d.hasData = false
+
+	if eventType == "" {
+		eventType = "message"
+	}
+	return &Event{
+		Type:  eventType,
+		Data:  strings.TrimSuffix(data, "\n"),
+		ID:    d.lastID,
+		Retry: d.retry,
+	}
+}
+
+// parseRetry parses a retry value, which must consist only of ASCII digits
+func parseRetry(value []byte) (int64, bool) {
+	if len(value) == 0 {
+		return 0, false
+	}
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+	}
+	ms, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil || ms > math.MaxInt64/int64(time.Millisecond) {
+		return 0, false
+	}
+	return ms, true
+}
+
+// scanLines is a bufio.SplitFunc for SSE line endings: CRLF, LF or a lone CR
+func scanLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\n' {
+			return i + 1, data[:i], nil
+		}
+		// A CR at the end of the buffer may be the first half of a CRLF
+		if i+1 == len(data) && !atEOF {
+			return 0, nil, nil
+		}
+		if i+1 < len(data) && data[i+1] == '\n' {
+			return i + 2, data[:i], nil
+		}
+		return i + 1, data[:i], nil
+	}
+
+	// The final line has no terminator; it can never complete an event but may
+	// still carry a field, so hand it to the decoder
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
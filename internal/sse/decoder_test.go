@@ -0,0 +1,212 @@
+package sse
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// readAll decodes every event in input
+func readAll(t *testing.T, r io.Reader) []Event {
+	t.Helper()
+
+	var events []Event
+	dec := NewDecoder(r)
+	for {
+		event, err := dec.Next()
+		if err == io.EOF {
+			return events
+		}
+		require.NoError(t, err)
+		events = append(events, *event)
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Event
+	}{
+		{
+			name:  "single data line",
+			input: "data: hello\n\n",
+			want:  []Event{{Type: "message", Data: "hello"}},
+		},
+		{
+			name:  "data without space",
+			input: "data:hello\n\n",
+			want:  []Event{{Type: "message", Data: "hello"}},
+		},
+		{
+			name:  "only one leading space is stripped",
+			input: "data:  hello \n\n",
+			want:  []Event{{Type: "message", Data: " hello "}},
+		},
+		{
+			name:  "multi-line data",
+			input: "data: first\ndata: second\ndata\n\n",
+			want:  []Event{{Type: "message", Data: "first\nsecond\n"}},
+		},
+		{
+			name:  "named event",
+			input: "event: message_start\ndata: {}\n\nevent: ping\ndata: {}\n\ndata: unnamed\n\n",
+			want: []Event{
+				{Type: "message_start", Data: "{}"},
+				{Type: "ping", Data: "{}"},
+				{Type: "message", Data: "unnamed"},
+			},
+		},
+		{
+			name:  "comments are ignored",
+			input: ": OPENROUTER PROCESSING\n\n:keep-alive\ndata: x\n: inline\n\n",
+			want:  []Event{{Type: "message", Data: "x"}},
+		},
+		{
+			name:  "id persists across events",
+			input: "id: 1\ndata: a\n\ndata: b\n\nid\ndata: c\n\n",
+			want: []Event{
+				{Type: "message", Data: "a", ID: "1"},
+				{Type: "message", Data: "b", ID: "1"},
+				{Type: "message", Data: "c"},
+			},
+		},
+		{
+			name:  "id containing NULL is ignored",
+			input: "id: 1\n\nid: a\x00b\ndata: x\n\n",
+			want:  []Event{{Type: "message", Data: "x", ID: "1"}},
+		},
+		{
+			name:  "retry",
+			input: "retry: 1500\ndata: a\n\nretry: 1s\ndata: b\n\n",
+			want: []Event{
+				{Type: "message", Data: "a", Retry: 1500 * time.Millisecond},
+				{Type: "message", Data: "b", Retry: 1500 * time.Millisecond},
+			},
+		},
+		{
+			name:  "CRLF and CR line endings",
+			input: "data: a\r\n\r\ndata: b\r\rdata: c\r\n\n",
+			want: []Event{
+				{Type: "message", Data: "a"},
+				{Type: "message", Data: "b"},
+				{Type: "message", Data: "c"},
+			},
+		},
+		{
+			name:  "byte order mark",
+			input: "\xEF\xBB\xBFdata: a\n\n",
+			want:  []Event{{Type: "message", Data: "a"}},
+		},
+		{
+			name:  "event without data is not dispatched",
+			input: "event: ping\n\ndata: a\n\n",
+			want:  []Event{{Type: "message", Data: "a"}},
+		},
+		{
+			name:  "unknown fields are ignored",
+			input: "foo: bar\ndata: a\n\n",
+			want:  []Event{{Type: "message", Data: "a"}},
+		},
+		{
+			name:  "unterminated event is discarded",
+			input: "data: a\n\ndata: b\n",
+			want:  []Event{{Type: "message", Data: "a"}},
+		},
+		{
+			name:  "empty stream",
+			input: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, readAll(t, strings.NewReader(tt.input)))
+
+			// Decoding must not depend on how the input is split into reads
+			require.Equal(t, tt.want, readAll(t, iotest.OneByteReader(strings.NewReader(tt.input))))
+		})
+	}
+}
+
+func TestDecoderLargeLine(t *testing.T) {
+	// Larger than bufio.Scanner's default 64KB token limit
+	payload := strings.Repeat("x", 1024*1024)
+	events := readAll(t, strings.NewReader("data: "+payload+"\n\n"))
+	require.Len(t, events, 1)
+	require.Equal(t, payload, events[0].Data)
+}
+
+func TestDecoderMaxLineSize(t *testing.T) {
+	dec := NewDecoderSize(strings.NewReader("data: "+strings.Repeat("x", 1024)+"\n\n"), 512)
+	_, err := dec.Next()
+	require.ErrorIs(t, err, bufio.ErrTooLong)
+}
+
+func TestDecoderReadError(t *testing.T) {
+	readErr := errors.New("connection reset")
+	dec := NewDecoder(io.MultiReader(strings.NewReader("data: a\n\n"), iotest.ErrReader(readErr)))
+
+	event, err := dec.Next()
+	require.NoError(t, err)
+	require.Equal(t, "a", event.Data)
+
+	_, err = dec.Next()
+	require.ErrorIs(t, err, readErr)
+}
+
+func FuzzDecoder(f *testing.F) {
+	f.Add("data: hello\n\n")
+	f.Add("event: a\r\ndata: b\r\ndata: c\r\n\r\n")
+	f.Add("id: 1\rretry: 10\rdata\r\r")
+	f.Add(": comment\n\xEF\xBB\xBFdata:x\n\n")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		whole := readAll(t, strings.NewReader(input))
+		split := readAll(t, iotest.OneByteReader(strings.NewReader(input)))
+		require.Equal(t, whole, split)
+
+		for _, event := range whole {
+			require.NotEmpty(t, event.Type)
+			require.NotContains(t, event.ID, "\x00")
+			require.GreaterOrEqual(t, event.Retry, time.Duration(0))
+		}
+	})
+}
+
+func BenchmarkDecoder(b *testing.B) {
+	chunk := `data: {"id":"chatcmpl-123","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"content":"Hello"},"finish_reason":null}]}` + "\n\n"
+	input := strings.Repeat(chunk, 1000)
+
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		dec := NewDecoder(strings.NewReader(input))
+		for {
+			if _, err := dec.Next(); err != nil {
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkDecoderLargeEvent(b *testing.B) {
+	input := "event: response.completed\ndata: " + strings.Repeat("x", 512*1024) + "\n\n"
+
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		dec := NewDecoder(strings.NewReader(input))
+		for {
+			if _, err := dec.Next(); err != nil {
+				break
+			}
+		}
+	}
+}
@@ -6,11 +6,15 @@ import (
 	"github.com/c0rtexR/llm_service/internal/provider/gemini"
 	"github.com/c0rtexR/llm_service/internal/provider/openai"
 	"github.com/c0rtexR/llm_service/internal/provider/openrouter"
+	"github.com/c0rtexR/llm_service/internal/provider/tgi"
 )
 
 // Re-export the LLMProvider interface
 type LLMProvider = provider.LLMProvider
 
+// Re-export the BatchProvider interface
+type BatchProvider = provider.BatchProvider
+
 // Re-export the Config struct
 type Config = provider.Config
 
@@ -19,6 +23,10 @@ func NewOpenAI(cfg *Config) LLMProvider {
 	return openai.New(cfg)
 }
 
+func NewOpenAIResponses(cfg *Config) LLMProvider {
+	return openai.New(cfg).WithResponsesAPI()
+}
+
 func NewAnthropic(cfg *Config) LLMProvider {
 	return anthropic.New(cfg)
 }
@@ -30,3 +38,11 @@ func NewGemini(cfg *Config) (LLMProvider, error) {
 func NewOpenRouter(cfg *Config) LLMProvider {
 	return openrouter.New(cfg)
 }
+
+func NewTGI(cfg *Config) LLMProvider {
+	return tgi.New(cfg)
+}
+
+func NewTGIMessages(cfg *Config) LLMProvider {
+	return tgi.New(cfg).WithMessagesAPI()
+}
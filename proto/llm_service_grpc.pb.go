@@ -19,8 +19,13 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	LLMService_Invoke_FullMethodName       = "/llm.v1.LLMService/Invoke"
-	LLMService_InvokeStream_FullMethodName = "/llm.v1.LLMService/InvokeStream"
+	LLMService_Invoke_FullMethodName             = "/llm.v1.LLMService/Invoke"
+	LLMService_InvokeStream_FullMethodName       = "/llm.v1.LLMService/InvokeStream"
+	LLMService_ResumeStream_FullMethodName       = "/llm.v1.LLMService/ResumeStream"
+	LLMService_CancelStream_FullMethodName       = "/llm.v1.LLMService/CancelStream"
+	LLMService_CreateBatch_FullMethodName        = "/llm.v1.LLMService/CreateBatch"
+	LLMService_GetBatch_FullMethodName           = "/llm.v1.LLMService/GetBatch"
+	LLMService_StreamBatchResults_FullMethodName = "/llm.v1.LLMService/StreamBatchResults"
 )
 
 // LLMServiceClient is the client API for LLMService service.
@@ -33,6 +38,18 @@ type LLMServiceClient interface {
 	Invoke(ctx context.Context, in *LLMRequest, opts ...grpc.CallOption) (*LLMResponse, error)
 	// InvokeStream sends a request to an LLM provider and returns a stream of responses
 	InvokeStream(ctx context.Context, in *LLMRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LLMStreamResponse], error)
+	// ResumeStream reconnects to a stream after a dropped connection, replaying the chunks
+	// after last_sequence and then following the live remainder
+	ResumeStream(ctx context.Context, in *ResumeStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LLMStreamResponse], error)
+	// CancelStream stops the generation of a resumable stream, which otherwise runs on
+	// after its client disconnects
+	CancelStream(ctx context.Context, in *CancelStreamRequest, opts ...grpc.CallOption) (*CancelStreamResponse, error)
+	// CreateBatch submits a set of requests for asynchronous batch processing
+	CreateBatch(ctx context.Context, in *CreateBatchRequest, opts ...grpc.CallOption) (*BatchJob, error)
+	// GetBatch returns the current status of a batch
+	GetBatch(ctx context.Context, in *GetBatchRequest, opts ...grpc.CallOption) (*BatchJob, error)
+	// StreamBatchResults streams the results of an ended batch
+	StreamBatchResults(ctx context.Context, in *GetBatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BatchResult], error)
 }
 
 type lLMServiceClient struct {
@@ -72,6 +89,74 @@ func (c *lLMServiceClient) InvokeStream(ctx context.Context, in *LLMRequest, opt
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type LLMService_InvokeStreamClient = grpc.ServerStreamingClient[LLMStreamResponse]
 
+func (c *lLMServiceClient) ResumeStream(ctx context.Context, in *ResumeStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LLMStreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LLMService_ServiceDesc.Streams[1], LLMService_ResumeStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ResumeStreamRequest, LLMStreamResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LLMService_ResumeStreamClient = grpc.ServerStreamingClient[LLMStreamResponse]
+
+func (c *lLMServiceClient) CancelStream(ctx context.Context, in *CancelStreamRequest, opts ...grpc.CallOption) (*CancelStreamResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelStreamResponse)
+	err := c.cc.Invoke(ctx, LLMService_CancelStream_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lLMServiceClient) CreateBatch(ctx context.Context, in *CreateBatchRequest, opts ...grpc.CallOption) (*BatchJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchJob)
+	err := c.cc.Invoke(ctx, LLMService_CreateBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lLMServiceClient) GetBatch(ctx context.Context, in *GetBatchRequest, opts ...grpc.CallOption) (*BatchJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchJob)
+	err := c.cc.Invoke(ctx, LLMService_GetBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lLMServiceClient) StreamBatchResults(ctx context.Context, in *GetBatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BatchResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LLMService_ServiceDesc.Streams[2], LLMService_StreamBatchResults_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetBatchRequest, BatchResult]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LLMService_StreamBatchResultsClient = grpc.ServerStreamingClient[BatchResult]
+
 // LLMServiceServer is the server API for LLMService service.
 // All implementations must embed UnimplementedLLMServiceServer
 // for forward compatibility.
@@ -82,6 +167,18 @@ type LLMServiceServer interface {
 	Invoke(context.Context, *LLMRequest) (*LLMResponse, error)
 	// InvokeStream sends a request to an LLM provider and returns a stream of responses
 	InvokeStream(*LLMRequest, grpc.ServerStreamingServer[LLMStreamResponse]) error
+	// ResumeStream reconnects to a stream after a dropped connection, replaying the chunks
+	// after last_sequence and then following the live remainder
+	ResumeStream(*ResumeStreamRequest, grpc.ServerStreamingServer[LLMStreamResponse]) error
+	// CancelStream stops the generation of a resumable stream, which otherwise runs on
+	// after its client disconnects
+	CancelStream(context.Context, *CancelStreamRequest) (*CancelStreamResponse, error)
+	// CreateBatch submits a set of requests for asynchronous batch processing
+	CreateBatch(context.Context, *CreateBatchRequest) (*BatchJob, error)
+	// GetBatch returns the current status of a batch
+	GetBatch(context.Context, *GetBatchRequest) (*BatchJob, error)
+	// StreamBatchResults streams the results of an ended batch
+	StreamBatchResults(*GetBatchRequest, grpc.ServerStreamingServer[BatchResult]) error
 	mustEmbedUnimplementedLLMServiceServer()
 }
 
@@ -98,6 +195,21 @@ func (UnimplementedLLMServiceServer) Invoke(context.Context, *LLMRequest) (*LLMR
 func (UnimplementedLLMServiceServer) InvokeStream(*LLMRequest, grpc.ServerStreamingServer[LLMStreamResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method InvokeStream not implemented")
 }
+func (UnimplementedLLMServiceServer) ResumeStream(*ResumeStreamRequest, grpc.ServerStreamingServer[LLMStreamResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ResumeStream not implemented")
+}
+func (UnimplementedLLMServiceServer) CancelStream(context.Context, *CancelStreamRequest) (*CancelStreamResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelStream not implemented")
+}
+func (UnimplementedLLMServiceServer) CreateBatch(context.Context, *CreateBatchRequest) (*BatchJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBatch not implemented")
+}
+func (UnimplementedLLMServiceServer) GetBatch(context.Context, *GetBatchRequest) (*BatchJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBatch not implemented")
+}
+func (UnimplementedLLMServiceServer) StreamBatchResults(*GetBatchRequest, grpc.ServerStreamingServer[BatchResult]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamBatchResults not implemented")
+}
 func (UnimplementedLLMServiceServer) mustEmbedUnimplementedLLMServiceServer() {}
 func (UnimplementedLLMServiceServer) testEmbeddedByValue()                    {}
 
@@ -148,6 +260,82 @@ func _LLMService_InvokeStream_Handler(srv interface{}, stream grpc.ServerStream)
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type LLMService_InvokeStreamServer = grpc.ServerStreamingServer[LLMStreamResponse]
 
+func _LLMService_ResumeStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ResumeStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LLMServiceServer).ResumeStream(m, &grpc.GenericServerStream[ResumeStreamRequest, LLMStreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LLMService_ResumeStreamServer = grpc.ServerStreamingServer[LLMStreamResponse]
+
+func _LLMService_CancelStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelStreamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMServiceServer).CancelStream(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMService_CancelStream_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMServiceServer).CancelStream(ctx, req.(*CancelStreamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LLMService_CreateBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMServiceServer).CreateBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMService_CreateBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMServiceServer).CreateBatch(ctx, req.(*CreateBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LLMService_GetBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMServiceServer).GetBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMService_GetBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMServiceServer).GetBatch(ctx, req.(*GetBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LLMService_StreamBatchResults_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetBatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LLMServiceServer).StreamBatchResults(m, &grpc.GenericServerStream[GetBatchRequest, BatchResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LLMService_StreamBatchResultsServer = grpc.ServerStreamingServer[BatchResult]
+
 // LLMService_ServiceDesc is the grpc.ServiceDesc for LLMService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -159,6 +347,18 @@ var LLMService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Invoke",
 			Handler:    _LLMService_Invoke_Handler,
 		},
+		{
+			MethodName: "CancelStream",
+			Handler:    _LLMService_CancelStream_Handler,
+		},
+		{
+			MethodName: "CreateBatch",
+			Handler:    _LLMService_CreateBatch_Handler,
+		},
+		{
+			MethodName: "GetBatch",
+			Handler:    _LLMService_GetBatch_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -166,6 +366,246 @@ var LLMService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _LLMService_InvokeStream_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ResumeStream",
+			Handler:       _LLMService_ResumeStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamBatchResults",
+			Handler:       _LLMService_StreamBatchResults_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/llm_service.proto",
+}
+
+const (
+	AdminService_ListCircuits_FullMethodName = "/llm.v1.AdminService/ListCircuits"
+	AdminService_ResetCircuit_FullMethodName = "/llm.v1.AdminService/ResetCircuit"
+	AdminService_ListAPIKeys_FullMethodName  = "/llm.v1.AdminService/ListAPIKeys"
+	AdminService_ListLimits_FullMethodName   = "/llm.v1.AdminService/ListLimits"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AdminService exposes the operational state of the service
+type AdminServiceClient interface {
+	// ListCircuits returns the state of every provider circuit breaker, and of the model
+	// circuit breakers of models that have failed since their circuit last closed
+	ListCircuits(ctx context.Context, in *ListCircuitsRequest, opts ...grpc.CallOption) (*ListCircuitsResponse, error)
+	// ResetCircuit closes a circuit breaker, e.g. once a vendor incident is resolved
+	ResetCircuit(ctx context.Context, in *ResetCircuitRequest, opts ...grpc.CallOption) (*CircuitInfo, error)
+	// ListAPIKeys returns the state and usage of every pooled API key
+	ListAPIKeys(ctx context.Context, in *ListAPIKeysRequest, opts ...grpc.CallOption) (*ListAPIKeysResponse, error)
+	// ListLimits returns the concurrency and queue state of every limited provider and model
+	ListLimits(ctx context.Context, in *ListLimitsRequest, opts ...grpc.CallOption) (*ListLimitsResponse, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) ListCircuits(ctx context.Context, in *ListCircuitsRequest, opts ...grpc.CallOption) (*ListCircuitsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCircuitsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListCircuits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ResetCircuit(ctx context.Context, in *ResetCircuitRequest, opts ...grpc.CallOption) (*CircuitInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CircuitInfo)
+	err := c.cc.Invoke(ctx, AdminService_ResetCircuit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListAPIKeys(ctx context.Context, in *ListAPIKeysRequest, opts ...grpc.CallOption) (*ListAPIKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAPIKeysResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListAPIKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListLimits(ctx context.Context, in *ListLimitsRequest, opts ...grpc.CallOption) (*ListLimitsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLimitsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListLimits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility.
+//
+// AdminService exposes the operational state of the service
+type AdminServiceServer interface {
+	// ListCircuits returns the state of every provider circuit breaker, and of the model
+	// circuit breakers of models that have failed since their circuit last closed
+	ListCircuits(context.Context, *ListCircuitsRequest) (*ListCircuitsResponse, error)
+	// ResetCircuit closes a circuit breaker, e.g. once a vendor incident is resolved
+	ResetCircuit(context.Context, *ResetCircuitRequest) (*CircuitInfo, error)
+	// ListAPIKeys returns the state and usage of every pooled API key
+	ListAPIKeys(context.Context, *ListAPIKeysRequest) (*ListAPIKeysResponse, error)
+	// ListLimits returns the concurrency and queue state of every limited provider and model
+	ListLimits(context.Context, *ListLimitsRequest) (*ListLimitsResponse, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServiceServer struct{}
+
+func (UnimplementedAdminServiceServer) ListCircuits(context.Context, *ListCircuitsRequest) (*ListCircuitsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCircuits not implemented")
+}
+func (UnimplementedAdminServiceServer) ResetCircuit(context.Context, *ResetCircuitRequest) (*CircuitInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetCircuit not implemented")
+}
+func (UnimplementedAdminServiceServer) ListAPIKeys(context.Context, *ListAPIKeysRequest) (*ListAPIKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAPIKeys not implemented")
+}
+func (UnimplementedAdminServiceServer) ListLimits(context.Context, *ListLimitsRequest) (*ListLimitsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLimits not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_ListCircuits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCircuitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListCircuits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListCircuits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListCircuits(ctx, req.(*ListCircuitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ResetCircuit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetCircuitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ResetCircuit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ResetCircuit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ResetCircuit(ctx, req.(*ResetCircuitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListAPIKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAPIKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListAPIKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListAPIKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListAPIKeys(ctx, req.(*ListAPIKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLimitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListLimits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListLimits(ctx, req.(*ListLimitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "llm.v1.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListCircuits",
+			Handler:    _AdminService_ListCircuits_Handler,
+		},
+		{
+			MethodName: "ResetCircuit",
+			Handler:    _AdminService_ResetCircuit_Handler,
+		},
+		{
+			MethodName: "ListAPIKeys",
+			Handler:    _AdminService_ListAPIKeys_Handler,
+		},
+		{
+			MethodName: "ListLimits",
+			Handler:    _AdminService_ListLimits_Handler,
+		},
 	},
+	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/llm_service.proto",
 }